@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/0xJWLabs/discordo/internal/markdown"
+	"github.com/0xJWLabs/tview"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/ningen/v3/discordmd"
+	"github.com/gdamore/tcell/v2"
+)
+
+// Shows the topic, flags, slowmode and member count of the selected channel above its messages.
+type ChannelHeader struct {
+	*tview.TextView
+	cfg *config.Config
+	app *tview.Application
+
+	channel discord.Channel
+}
+
+func newChannelHeader(app *tview.Application, cfg *config.Config) *ChannelHeader {
+	ch := &ChannelHeader{
+		TextView: tview.NewTextView(),
+		cfg:      cfg,
+		app:      app,
+	}
+
+	ch.SetDynamicColors(true)
+	ch.SetWrap(false)
+	ch.SetTextColor(tcell.GetColor(cfg.Theme.MessagesText.ContentColor))
+	ch.SetBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+	return ch
+}
+
+func (ch *ChannelHeader) reset() {
+	ch.channel = discord.Channel{}
+	ch.Clear()
+	layout.setChannelHeaderVisible(false)
+}
+
+func (ch *ChannelHeader) setChannel(c discord.Channel) {
+	ch.channel = c
+	if c.GuildID.IsValid() {
+		discordState.MemberState.RequestMemberList(c.GuildID, memberListChannelID(c), 0)
+	}
+
+	ch.draw()
+	layout.setChannelHeaderVisible(true)
+}
+
+// Redraws the member count once the member list of the guild is updated.
+func (ch *ChannelHeader) onListUpdate(gID discord.GuildID) {
+	if ch.channel.ID.IsValid() && ch.channel.GuildID == gID {
+		ch.draw()
+	}
+}
+
+func (ch *ChannelHeader) draw() {
+	c := ch.channel
+	var parts []string
+
+	if c.Topic != "" {
+		var b strings.Builder
+		src := []byte(c.Topic)
+		ast := discordmd.ParseWithMessage(src, *discordState.Cabinet, &discord.Message{ChannelID: c.ID, GuildID: c.GuildID}, false)
+		markdown.DefaultRenderer.Render(&b, src, ast)
+		parts = append(parts, strings.Join(strings.Fields(b.String()), " "))
+	}
+
+	if c.NSFW {
+		parts = append(parts, "[red]NSFW[-]")
+	}
+
+	if c.UserRateLimit > 0 {
+		parts = append(parts, "Slowmode: "+formatSeconds(time.Duration(c.UserRateLimit)*time.Second))
+	}
+
+	if count := channelMemberCount(c); count > 0 {
+		parts = append(parts, fmt.Sprintf("%d members", count))
+	}
+
+	if ok, _ := canSendMessages(c); !ok {
+		parts = append(parts, "[red]Read-only[-]")
+	}
+
+	ch.SetText(strings.Join(parts, " [::d]│[::-] "))
+	ch.ScrollToBeginning()
+}
+
+// Returns the number of members of the guild as known from its member list, or the number of recipients of direct messages including the current user.
+func channelMemberCount(c discord.Channel) int {
+	if !c.GuildID.IsValid() {
+		return len(c.DMRecipients) + 1
+	}
+
+	if list, err := discordState.MemberState.GetMemberList(c.GuildID, memberListChannelID(c)); err == nil {
+		return list.MemberCount()
+	}
+
+	if g, err := discordState.Cabinet.Guild(c.GuildID); err == nil {
+		return int(g.ApproximateMembers)
+	}
+
+	return 0
+}
+
+// Reports whether the current user can send messages in the channel, and explains why not if they cannot.
+func canSendMessages(c discord.Channel) (bool, string) {
+	if !c.GuildID.IsValid() {
+		return true, ""
+	}
+
+	// Threads inherit the permissions of their parent channel.
+	pID, perm := c.ID, discord.PermissionSendMessages
+	if isThread(c) {
+		pID, perm = c.ParentID, discord.PermissionSendMessagesInThreads
+	}
+
+	ps, err := discordState.Permissions(pID, discordState.Ready().User.ID)
+	if err != nil {
+		// Let Discord decide.
+		return true, ""
+	}
+
+	if !ps.Has(perm) {
+		return false, "You do not have permission to send messages in this channel."
+	}
+
+	return true, ""
+}
+
+// Reports whether the slowmode of the channel applies to the current user, which it does not to those who can manage its messages or the channel itself.
+func slowmodeApplies(c discord.Channel) bool {
+	if c.UserRateLimit <= 0 || !c.GuildID.IsValid() {
+		return false
+	}
+
+	pID := c.ID
+	if isThread(c) {
+		pID = c.ParentID
+	}
+
+	ps, err := discordState.Permissions(pID, discordState.Ready().User.ID)
+	if err != nil {
+		return true
+	}
+
+	return !ps.Has(discord.PermissionManageMessages) && !ps.Has(discord.PermissionManageChannels)
+}
+
+// Formats the duration like 1h30m, 5m or 10s.
+func formatSeconds(d time.Duration) string {
+	d = d.Round(time.Second)
+	h, m, s := int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60
+
+	var b strings.Builder
+	if h > 0 {
+		fmt.Fprintf(&b, "%dh", h)
+	}
+	if m > 0 {
+		fmt.Fprintf(&b, "%dm", m)
+	}
+	if s > 0 || b.Len() == 0 {
+		fmt.Fprintf(&b, "%ds", s)
+	}
+
+	return b.String()
+}
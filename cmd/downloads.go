@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/0xJWLabs/tview"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/gdamore/tcell/v2"
+)
+
+const (
+	// How often the progress of the downloads is redrawn.
+	downloadsRedrawInterval = 200 * time.Millisecond
+	// How long finished downloads stay in the status line.
+	downloadsFinishedTimeout = 5 * time.Second
+)
+
+type download struct {
+	attachment discord.Attachment
+	cancel     context.CancelFunc
+	// Written to by the goroutine of the download and read when drawing.
+	written atomic.Int64
+
+	// The path the attachment is saved to once the file is created.
+	path string
+	done bool
+	err  error
+}
+
+// A single line that shows the progress of the attachments being downloaded. It is hidden when there are none.
+type Downloads struct {
+	*tview.TextView
+	cfg *config.Config
+	app *tview.Application
+
+	downloads []*download
+	// Closed to stop redrawing the progress once every download is finished.
+	stopRedraw chan struct{}
+}
+
+func newDownloads(app *tview.Application, cfg *config.Config) *Downloads {
+	d := &Downloads{
+		TextView: tview.NewTextView(),
+		cfg:      cfg,
+		app:      app,
+	}
+
+	d.SetDynamicColors(true)
+	d.SetWrap(false)
+	d.SetTextColor(tcell.GetColor(cfg.Theme.MessagesText.ContentColor))
+	d.SetBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+	d.SetBorderPadding(0, 0, 1, 1)
+
+	return d
+}
+
+// Returns the configured download directory with a leading ~ expanded.
+func (d *Downloads) dir() (string, error) {
+	dir := d.cfg.Downloads.Dir
+	if dir != "" && dir != "~" && !strings.HasPrefix(dir, "~/") {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	if dir == "" {
+		return filepath.Join(home, "Downloads"), nil
+	}
+
+	return filepath.Join(home, strings.TrimPrefix(dir, "~")), nil
+}
+
+func (d *Downloads) start(a discord.Attachment) {
+	ctx, cancel := context.WithCancel(context.Background())
+	dl := &download{attachment: a, cancel: cancel}
+	d.downloads = append(d.downloads, dl)
+
+	if d.stopRedraw == nil {
+		d.stopRedraw = make(chan struct{})
+		go d.redraw(d.stopRedraw)
+	}
+
+	d.draw()
+
+	go func() {
+		path, err := d.save(ctx, dl)
+		d.app.QueueUpdateDraw(func() {
+			d.finish(dl, path, err)
+		})
+	}()
+}
+
+// Redraws the progress until every download is finished.
+func (d *Downloads) redraw(stop <-chan struct{}) {
+	ticker := time.NewTicker(downloadsRedrawInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.app.QueueUpdateDraw(d.draw)
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (d *Downloads) cancelAll() {
+	for _, dl := range d.downloads {
+		if !dl.done {
+			dl.cancel()
+		}
+	}
+}
+
+func (d *Downloads) save(ctx context.Context, dl *download) (string, error) {
+	dir, err := d.dir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get download directory: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("failed to create download directory: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, dl.attachment.URL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get attachment: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	f, err := createUnique(dir, dl.attachment.Filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, io.TeeReader(resp.Body, progressWriter{&dl.written})); err != nil {
+		// Do not leave partial files behind.
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+
+	return f.Name(), nil
+}
+
+type progressWriter struct {
+	written *atomic.Int64
+}
+
+func (pw progressWriter) Write(b []byte) (int, error) {
+	pw.written.Add(int64(len(b)))
+	return len(b), nil
+}
+
+// Creates the file in the directory, appending " (n)" to its name if one with the same name already exists.
+func createUnique(dir string, name string) (*os.File, error) {
+	name = filepath.Base(name)
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	for i := 0; ; i++ {
+		path := filepath.Join(dir, name)
+		if i > 0 {
+			path = filepath.Join(dir, fmt.Sprintf("%s (%d)%s", base, i, ext))
+		}
+
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to create file: %w", err)
+		}
+
+		return f, nil
+	}
+}
+
+func (d *Downloads) finish(dl *download, path string, err error) {
+	dl.done = true
+	dl.path = path
+	dl.err = err
+
+	a := dl.attachment
+	switch {
+	case errors.Is(err, context.Canceled):
+	case err != nil:
+		slog.Error("failed to download attachment", "err", err, "url", a.URL)
+	default:
+		d.openFile(path, a.ContentType)
+	}
+
+	if !slices.ContainsFunc(d.downloads, func(dl *download) bool { return !dl.done }) {
+		close(d.stopRedraw)
+		d.stopRedraw = nil
+	}
+
+	d.draw()
+
+	time.AfterFunc(downloadsFinishedTimeout, func() {
+		d.app.QueueUpdateDraw(func() {
+			d.downloads = slices.DeleteFunc(d.downloads, func(dl2 *download) bool { return dl2 == dl })
+			d.draw()
+		})
+	})
+}
+
+// Opens the downloaded file with the command configured for its MIME type, if any.
+func (d *Downloads) openFile(path string, contentType string) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = mime.TypeByExtension(filepath.Ext(path))
+		mediaType, _, _ = mime.ParseMediaType(mediaType)
+	}
+
+	typ, _, _ := strings.Cut(mediaType, "/")
+	openWith := d.cfg.Downloads.OpenWith
+	command, ok := openWith[mediaType]
+	if !ok {
+		command, ok = openWith[typ+"/*"]
+	}
+	if !ok {
+		command, ok = openWith["*"]
+	}
+
+	args := strings.Fields(command)
+	if !ok || len(args) == 0 {
+		return
+	}
+
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	if err := cmd.Start(); err != nil {
+		slog.Error("failed to open downloaded file", "err", err, "command", command, "path", path)
+		return
+	}
+
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			slog.Error("failed to run command", "err", err, "command", command, "path", path)
+		}
+	}()
+}
+
+func (d *Downloads) draw() {
+	var parts []string
+	for _, dl := range d.downloads {
+		name := tview.Escape(dl.attachment.Filename)
+		switch {
+		case errors.Is(dl.err, context.Canceled):
+			parts = append(parts, fmt.Sprintf("[::d]%s cancelled[::-]", name))
+		case dl.err != nil:
+			parts = append(parts, fmt.Sprintf("[red]%s failed[-]", name))
+		case dl.done:
+			parts = append(parts, fmt.Sprintf("[green]Saved %s[-]", tview.Escape(dl.path)))
+		default:
+			written := dl.written.Load()
+			if size := int64(dl.attachment.Size); size > 0 {
+				parts = append(parts, fmt.Sprintf("%s %d%%", name, min(written*100/size, 100)))
+			} else {
+				parts = append(parts, fmt.Sprintf("%s %s", name, formatBytes(written)))
+			}
+		}
+	}
+
+	d.SetText(strings.Join(parts, " · "))
+	layout.setDownloadsVisible(len(d.downloads) > 0)
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/0xJWLabs/tview"
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+const emojiPickerPageName = "emoji_picker"
+
+// Commonly used unicode emojis and their shortcodes.
+var unicodeEmojis = [...][2]string{
+	{"thumbsup", "👍"}, {"thumbsdown", "👎"}, {"heart", "❤️"}, {"joy", "😂"},
+	{"rofl", "🤣"}, {"smile", "😄"}, {"grin", "😁"}, {"slight_smile", "🙂"},
+	{"wink", "😉"}, {"blush", "😊"}, {"heart_eyes", "😍"}, {"kissing_heart", "😘"},
+	{"thinking", "🤔"}, {"neutral_face", "😐"}, {"expressionless", "😑"}, {"unamused", "😒"},
+	{"rolling_eyes", "🙄"}, {"grimacing", "😬"}, {"relieved", "😌"}, {"pensive", "😔"},
+	{"sleeping", "😴"}, {"sweat_smile", "😅"}, {"sweat", "😓"}, {"cry", "😢"},
+	{"sob", "😭"}, {"angry", "😠"}, {"rage", "😡"}, {"scream", "😱"},
+	{"flushed", "😳"}, {"open_mouth", "😮"}, {"astonished", "😲"}, {"skull", "💀"},
+	{"clown", "🤡"}, {"nerd", "🤓"}, {"sunglasses", "😎"}, {"partying_face", "🥳"},
+	{"upside_down", "🙃"}, {"zany_face", "🤪"}, {"pleading_face", "🥺"}, {"eyes", "👀"},
+	{"ok_hand", "👌"}, {"clap", "👏"}, {"wave", "👋"}, {"pray", "🙏"},
+	{"raised_hands", "🙌"}, {"muscle", "💪"}, {"point_up", "☝️"}, {"v", "✌️"},
+	{"crossed_fingers", "🤞"}, {"handshake", "🤝"}, {"fire", "🔥"}, {"100", "💯"},
+	{"sparkles", "✨"}, {"star", "⭐"}, {"tada", "🎉"}, {"rocket", "🚀"},
+	{"white_check_mark", "✅"}, {"x", "❌"}, {"warning", "⚠️"}, {"question", "❓"},
+	{"exclamation", "❗"}, {"broken_heart", "💔"}, {"orange_heart", "🧡"}, {"yellow_heart", "💛"},
+	{"green_heart", "💚"}, {"blue_heart", "💙"}, {"purple_heart", "💜"}, {"black_heart", "🖤"},
+	{"poop", "💩"}, {"ghost", "👻"}, {"robot", "🤖"}, {"bug", "🐛"},
+	{"coffee", "☕"}, {"beer", "🍺"}, {"pizza", "🍕"}, {"cake", "🎂"},
+	{"gift", "🎁"}, {"trophy", "🏆"}, {"bell", "🔔"}, {"pushpin", "📌"},
+	{"memo", "📝"}, {"bulb", "💡"}, {"lock", "🔒"}, {"key", "🔑"},
+	{"hourglass", "⌛"}, {"zap", "⚡"}, {"rainbow", "🌈"}, {"cat", "🐱"},
+	{"dog", "🐶"}, {"arrow_up", "⬆️"}, {"arrow_down", "⬇️"}, {"plus", "➕"},
+}
+
+func emojiToString(e discord.Emoji) string {
+	if e.IsUnicode() {
+		return e.Name
+	}
+
+	return ":" + e.Name + ":"
+}
+
+func emojiEqual(a, b discord.Emoji) bool {
+	if a.ID.IsValid() || b.ID.IsValid() {
+		return a.ID == b.ID
+	}
+
+	return a.Name == b.Name
+}
+
+func newEmojiPicker(cfg *config.Config, gID discord.GuildID, done func(e discord.Emoji)) *picker {
+	var items []pickerItem
+	for _, e := range unicodeEmojis {
+		items = append(items, pickerItem{
+			text:      fmt.Sprintf("%s :%s:", e[1], e[0]),
+			reference: discord.Emoji{Name: e[1]},
+		})
+	}
+
+	// Custom emojis of every guild if the user has Nitro, of the current guild otherwise.
+	guilds, err := discordState.EmojiState.ForGuild(gID)
+	if err != nil {
+		slog.Error("failed to get guild emojis", "err", err, "guild_id", gID)
+	}
+
+	for _, g := range guilds {
+		for _, e := range g.Emojis {
+			items = append(items, pickerItem{
+				text:      fmt.Sprintf(":%s: (%s)", e.Name, tview.Escape(g.Name)),
+				reference: e,
+			})
+		}
+	}
+
+	p := newPicker(cfg, "Reactions")
+	p.setItems(items)
+	p.selectedFunc = func(item pickerItem) {
+		layout.hideModal(emojiPickerPageName)
+		done(item.reference.(discord.Emoji))
+	}
+	p.cancelFunc = func() {
+		layout.hideModal(emojiPickerPageName)
+	}
+
+	return p
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+)
+
+const filePickerPageName = "file_picker"
+
+// The reference of the entries of the file picker.
+type fileEntry struct {
+	path  string
+	isDir bool
+}
+
+// Browses the filesystem starting at the directory and toggles the selected files in the attachments of the message input.
+type filePicker struct {
+	*picker
+	dir string
+}
+
+func newFilePicker(cfg *config.Config, dir string) *filePicker {
+	fp := &filePicker{picker: newPicker(cfg, "")}
+	fp.selectedFunc = fp.onSelected
+	fp.cancelFunc = func() {
+		layout.hideModal(filePickerPageName)
+	}
+
+	fp.open(dir)
+	return fp
+}
+
+// Lists the entries of the directory, with the parent directory and then the subdirectories first.
+func (fp *filePicker) open(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		slog.Error("failed to read directory", "err", err, "dir", dir)
+		return
+	}
+
+	fp.dir = dir
+	layout.messageInput.attachDir = dir
+	fp.SetTitle(dir)
+
+	items := []pickerItem{{text: "../", reference: fileEntry{path: filepath.Dir(dir), isDir: true}}}
+	var files []pickerItem
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		isDir := e.IsDir()
+		// Follow symbolic links to directories.
+		if e.Type()&os.ModeSymlink != 0 {
+			if info, err := os.Stat(path); err == nil {
+				isDir = info.IsDir()
+			}
+		}
+
+		if isDir {
+			items = append(items, pickerItem{text: e.Name() + "/", reference: fileEntry{path: path, isDir: true}})
+		} else {
+			files = append(files, pickerItem{text: fp.fileText(path), reference: fileEntry{path: path}})
+		}
+	}
+
+	fp.setItems(append(items, files...))
+	fp.input.SetText("")
+	fp.list.SetCurrentItem(0)
+}
+
+// Marks the files that are already attached.
+func (fp *filePicker) fileText(path string) string {
+	name := filepath.Base(path)
+	if slices.Contains(layout.messageInput.attachments, path) {
+		return "+ " + name
+	}
+
+	return name
+}
+
+func (fp *filePicker) onSelected(item pickerItem) {
+	entry := item.reference.(fileEntry)
+	if entry.isDir {
+		fp.open(entry.path)
+		return
+	}
+
+	layout.messageInput.toggleAttachment(entry.path)
+
+	// Keep the picker open so that several files can be attached at once.
+	current := fp.list.GetCurrentItem()
+	for i := range fp.items {
+		if e := fp.items[i].reference.(fileEntry); e.path == entry.path {
+			fp.items[i].text = fp.fileText(e.path)
+		}
+	}
+
+	fp.onInputChanged(fp.input.GetText())
+	fp.list.SetCurrentItem(current)
+}
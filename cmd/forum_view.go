@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/0xJWLabs/tview"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"github.com/gdamore/tcell/v2"
+)
+
+const forumPostFormPageName = "forum_post_form"
+
+// The response of the endpoint the official client uses to list the posts of a forum, archived ones included.
+type forumThreadsResponse struct {
+	Threads       []discord.Channel `json:"threads"`
+	FirstMessages []discord.Message `json:"first_messages"`
+	HasMore       bool              `json:"has_more"`
+}
+
+type ForumView struct {
+	*tview.Table
+	cfg *config.Config
+	app *tview.Application
+
+	forum discord.Channel
+	posts []discord.Channel
+	// The authors of the first messages of the posts, keyed by post ID.
+	authors        map[discord.ChannelID]discord.User
+	sortByCreation bool
+}
+
+func newForumView(app *tview.Application, cfg *config.Config) *ForumView {
+	fv := &ForumView{
+		Table:   tview.NewTable(),
+		cfg:     cfg,
+		app:     app,
+		authors: make(map[discord.ChannelID]discord.User),
+	}
+
+	fv.SetFixed(1, 0)
+	fv.SetSelectable(true, false)
+	fv.SetSelectedFunc(fv.onSelected)
+	fv.SetInputCapture(fv.onInputCapture)
+	fv.SetBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+
+	fv.SetTitleColor(tcell.GetColor(cfg.Theme.TitleColor))
+	fv.SetFocusTitleColor(tcell.GetColor(cfg.Theme.FocusTitleColor))
+	fv.SetTitleAlign(tview.AlignLeft)
+	fv.SetTitlePadding(1, 1)
+
+	p := cfg.Theme.BorderPadding
+	fv.SetBorder(cfg.Theme.Border)
+	fv.SetBorderColor(tcell.GetColor(cfg.Theme.BorderColor))
+	fv.SetFocusBorderColor(tcell.GetColor(cfg.Theme.FocusBorderColor))
+	fv.SetBorderPadding(p[0], p[1], p[2], p[3])
+
+	return fv
+}
+
+// Shows the active posts of the forum right away and fetches the rest in the background.
+func (fv *ForumView) load(forum discord.Channel) {
+	fv.forum = forum
+	fv.posts = nil
+	clear(fv.authors)
+
+	cs, err := discordState.Cabinet.Channels(forum.GuildID)
+	if err != nil {
+		slog.Error("failed to get channels", "err", err, "guild_id", forum.GuildID)
+	}
+
+	for _, c := range cs {
+		if isThread(c) && c.ParentID == forum.ID {
+			fv.posts = append(fv.posts, c)
+		}
+	}
+
+	fv.draw()
+
+	go func() {
+		var resp forumThreadsResponse
+		err := discordState.RequestJSON(
+			&resp, "GET",
+			api.EndpointChannels+forum.ID.String()+"/threads/search",
+			httputil.WithSchema(discordState.Client, url.Values{
+				"sort_by":    {"last_message_time"},
+				"sort_order": {"desc"},
+				"limit":      {"25"},
+			}),
+		)
+		if err != nil {
+			slog.Error("failed to get forum posts", "err", err, "channel_id", forum.ID)
+			return
+		}
+
+		fv.app.QueueUpdateDraw(func() {
+			if fv.forum.ID != forum.ID {
+				return
+			}
+
+			for _, t := range resp.Threads {
+				if !slices.ContainsFunc(fv.posts, func(p discord.Channel) bool { return p.ID == t.ID }) {
+					fv.posts = append(fv.posts, t)
+				}
+			}
+
+			for _, m := range resp.FirstMessages {
+				fv.authors[m.ChannelID] = m.Author
+			}
+
+			fv.draw()
+		})
+	}()
+}
+
+func postCreatedAt(p discord.Channel) discord.Timestamp {
+	if p.ThreadMetadata != nil && p.ThreadMetadata.CreateTimestamp != nil {
+		return *p.ThreadMetadata.CreateTimestamp
+	}
+
+	return discord.NewTimestamp(p.ID.Time())
+}
+
+func postLastActivity(p discord.Channel) discord.Timestamp {
+	if p.LastMessageID.IsValid() {
+		return discord.NewTimestamp(p.LastMessageID.Time())
+	}
+
+	return postCreatedAt(p)
+}
+
+func (fv *ForumView) draw() {
+	sortKey := postLastActivity
+	sortName := "recent activity"
+	if fv.sortByCreation {
+		sortKey = postCreatedAt
+		sortName = "creation date"
+	}
+
+	slices.SortStableFunc(fv.posts, func(a, b discord.Channel) int {
+		return sortKey(b).Time().Compare(sortKey(a).Time())
+	})
+
+	fv.SetTitle(fmt.Sprintf("%s (sorted by %s)", layout.guildsTree.channelToString(fv.forum), sortName))
+
+	fv.Clear()
+	for col, header := range []string{"Title", "Tags", "Author", "Replies", "Last activity"} {
+		fv.SetCell(0, col, tview.NewTableCell(header).SetAttributes(tcell.AttrBold).SetSelectable(false))
+	}
+
+	for i, p := range fv.posts {
+		row := i + 1
+		fv.SetCell(row, 0, tview.NewTableCell(tview.Escape(p.Name)).SetReference(p.ID).SetExpansion(1))
+		fv.SetCell(row, 1, tview.NewTableCell(tview.Escape(fv.tagNames(p))))
+		fv.SetCell(row, 2, tview.NewTableCell(tview.Escape(fv.authorName(p))))
+		fv.SetCell(row, 3, tview.NewTableCell(fmt.Sprint(p.MessageCount)).SetAlign(tview.AlignRight))
+		fv.SetCell(row, 4, tview.NewTableCell(formatTimestamp(fv.cfg, postLastActivity(p).Time())))
+	}
+
+	fv.ScrollToBeginning()
+	fv.Select(1, 0)
+}
+
+func (fv *ForumView) tagNames(p discord.Channel) string {
+	var names []string
+	for _, tag := range fv.forum.AvailableTags {
+		if slices.Contains(p.AppliedTags, tag.ID) {
+			names = append(names, tag.Name)
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func (fv *ForumView) authorName(p discord.Channel) string {
+	if m, err := discordState.Cabinet.Member(p.GuildID, p.OwnerID); err == nil {
+		if m.Nick != "" {
+			return m.Nick
+		}
+
+		return m.User.DisplayOrUsername()
+	}
+
+	if u, ok := fv.authors[p.ID]; ok {
+		return u.DisplayOrUsername()
+	}
+
+	return ""
+}
+
+func (fv *ForumView) onSelected(row, _ int) {
+	pID, ok := fv.GetCell(row, 0).GetReference().(discord.ChannelID)
+	if !ok {
+		return
+	}
+
+	idx := slices.IndexFunc(fv.posts, func(p discord.Channel) bool { return p.ID == pID })
+	if idx == -1 {
+		return
+	}
+
+	// Archived posts are not in the cabinet, so the tree would not be able to find them otherwise.
+	if _, err := discordState.Cabinet.Channel(pID); err != nil {
+		if err := discordState.Cabinet.ChannelSet(&fv.posts[idx], false); err != nil {
+			slog.Error("failed to set forum post", "err", err, "channel_id", pID)
+		}
+	}
+
+	layout.guildsTree.selectChannel(pID)
+}
+
+func (fv *ForumView) onInputCapture(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Name() {
+	case fv.cfg.Keys.SelectPrevious:
+		return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+	case fv.cfg.Keys.SelectNext:
+		return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+	case fv.cfg.Keys.SelectFirst:
+		return tcell.NewEventKey(tcell.KeyHome, 0, tcell.ModNone)
+	case fv.cfg.Keys.SelectLast:
+		return tcell.NewEventKey(tcell.KeyEnd, 0, tcell.ModNone)
+
+	case fv.cfg.Keys.ForumView.ToggleSort:
+		fv.sortByCreation = !fv.sortByCreation
+		fv.draw()
+		return nil
+	case fv.cfg.Keys.ForumView.CreatePost:
+		fv.showPostForm()
+		return nil
+	}
+
+	return event
+}
+
+func (fv *ForumView) showPostForm() {
+	forum := fv.forum
+
+	form := tview.NewForm()
+	form.AddInputField("Title", "", 0, nil, nil)
+	for _, tag := range forum.AvailableTags {
+		form.AddCheckbox(tag.Name, false, nil)
+	}
+
+	form.AddButton("Write body", func() {
+		title := strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText())
+		if title == "" {
+			return
+		}
+
+		var tags []discord.TagID
+		for i, tag := range forum.AvailableTags {
+			if form.GetFormItem(i + 1).(*tview.Checkbox).IsChecked() {
+				tags = append(tags, tag.ID)
+			}
+		}
+
+		layout.hideModal(forumPostFormPageName)
+		fv.createPost(forum, title, tags)
+	})
+	form.AddButton("Cancel", func() {
+		layout.hideModal(forumPostFormPageName)
+	})
+	form.SetCancelFunc(func() {
+		layout.hideModal(forumPostFormPageName)
+	})
+
+	form.SetBackgroundColor(tcell.GetColor(fv.cfg.Theme.BackgroundColor))
+	form.SetFieldBackgroundColor(tcell.GetColor(fv.cfg.Theme.BackgroundColor))
+
+	form.SetTitle("New post in " + forum.Name)
+	form.SetTitleColor(tcell.GetColor(fv.cfg.Theme.TitleColor))
+	form.SetTitleAlign(tview.AlignLeft)
+	form.SetTitlePadding(1, 1)
+	form.SetBorder(fv.cfg.Theme.Border)
+	form.SetBorderColor(tcell.GetColor(fv.cfg.Theme.FocusBorderColor))
+
+	// Every form item takes up two rows, the title and the buttons one more each.
+	layout.showModal(forumPostFormPageName, form, 60, min(2*(len(forum.AvailableTags)+1)+5, 40))
+}
+
+func (fv *ForumView) createPost(forum discord.Channel, title string, tags []discord.TagID) {
+	body, err := openEditor(fv.app, fv.cfg, "")
+	if err != nil {
+		slog.Error("failed to write forum post body", "err", err)
+		return
+	}
+
+	if body == "" {
+		return
+	}
+
+	data := struct {
+		Name        string          `json:"name"`
+		AppliedTags []discord.TagID `json:"applied_tags,omitempty"`
+		Message     struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	}{Name: title, AppliedTags: tags}
+	data.Message.Content = body
+
+	go func() {
+		var post *discord.Channel
+		err := discordState.RequestJSON(
+			&post, "POST",
+			api.EndpointChannels+forum.ID.String()+"/threads",
+			httputil.WithJSONBody(data),
+		)
+		if err != nil {
+			slog.Error("failed to create forum post", "err", err, "channel_id", forum.ID)
+			return
+		}
+
+		if err := discordState.Cabinet.ChannelSet(post, false); err != nil {
+			slog.Error("failed to set forum post", "err", err, "channel_id", post.ID)
+		}
+
+		fv.app.QueueUpdateDraw(func() {
+			layout.guildsTree.onThreadCreate(*post)
+			layout.guildsTree.selectChannel(post.ID)
+		})
+	}()
+}
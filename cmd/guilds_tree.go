@@ -3,12 +3,14 @@ package cmd
 import (
 	"fmt"
 	"log/slog"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/0xJWLabs/discordo/internal/config"
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/ningen/v3"
 	"github.com/gdamore/tcell/v2"
 	"github.com/0xJWLabs/tview"
 )
@@ -17,7 +19,10 @@ type GuildsTree struct {
 	*tview.TreeView
 	cfg               *config.Config
 	app               *tview.Application
+	dmNode            *tview.TreeNode
+	mentionsNode      *tview.TreeNode
 	selectedChannelID discord.ChannelID
+	recents           *recentChannels
 }
 
 func newGuildsTree(app *tview.Application, cfg *config.Config) *GuildsTree {
@@ -25,6 +30,7 @@ func newGuildsTree(app *tview.Application, cfg *config.Config) *GuildsTree {
 		TreeView: tview.NewTreeView(),
 		cfg:      cfg,
 		app:      app,
+		recents:  loadRecentChannels(),
 	}
 
 	root := tview.NewTreeNode("")
@@ -78,10 +84,135 @@ func (gt *GuildsTree) createFolderNode(folder gateway.GuildFolder) {
 func (gt *GuildsTree) createGuildNode(n *tview.TreeNode, g discord.Guild) {
 	guildNode := tview.NewTreeNode(g.Name)
 	guildNode.SetReference(g.ID)
-	guildNode.SetColor(tcell.GetColor(gt.cfg.Theme.GuildsTree.GuildColor))
+	gt.updateGuildNode(guildNode, g)
 	n.AddChild(guildNode)
 }
 
+// Shows whether the node has unread messages or mentions, or is muted, in its text and color.
+func (gt *GuildsTree) setNodeState(n *tview.TreeNode, text string, color string, ind ningen.UnreadIndication, mentions int, muted bool) {
+	theme := gt.cfg.Theme.GuildsTree
+	text = tview.Escape(text)
+	// Highlighted messages count as mentions without ningen knowing about them.
+	if mentions > 0 {
+		ind = ningen.ChannelMentioned
+	}
+
+	switch {
+	case ind == ningen.ChannelMentioned:
+		color = theme.MentionColor
+		text = "[::b]" + text + "[::-]"
+	case ind == ningen.ChannelUnread:
+		color = theme.UnreadColor
+		text = "[::b]" + text + "[::-]"
+	case muted:
+		color = theme.MutedColor
+		text = "[::d]" + text + "[::-]"
+	}
+
+	if mentions > 0 {
+		text += fmt.Sprintf(" [%s::b](%d)[-::-]", theme.MentionColor, mentions)
+	}
+
+	n.SetText(text)
+	n.SetColor(tcell.GetColor(color))
+}
+
+// Returns the number of unread mentions and highlighted messages in the channel.
+func mentionCount(cID discord.ChannelID) int {
+	count := layout.highlights.unreadCount(cID)
+	if rs := discordState.ReadState.ReadState(cID); rs != nil {
+		count += rs.MentionCount
+	}
+
+	return count
+}
+
+func (gt *GuildsTree) updateGuildNode(n *tview.TreeNode, g discord.Guild) {
+	var mentions int
+	cs, err := discordState.Cabinet.Channels(g.ID)
+	if err != nil {
+		slog.Error("failed to get channels", "err", err, "guild_id", g.ID)
+	}
+
+	for _, c := range cs {
+		mentions += mentionCount(c.ID)
+	}
+
+	ind := discordState.GuildIsUnread(g.ID, ningen.GuildUnreadOpts{})
+	muted := discordState.MutedState.Guild(g.ID, false)
+	gt.setNodeState(n, g.Name, gt.cfg.Theme.GuildsTree.GuildColor, ind, mentions, muted)
+}
+
+func (gt *GuildsTree) updateChannelNode(n *tview.TreeNode, c discord.Channel) {
+	ind := discordState.ChannelIsUnread(c.ID, ningen.UnreadOpts{})
+	muted := discordState.ChannelIsMuted(c.ID, ningen.UnreadOpts{})
+	gt.setNodeState(n, gt.channelToString(c), gt.cfg.Theme.GuildsTree.ChannelColor, ind, mentionCount(c.ID), muted)
+}
+
+func (gt *GuildsTree) updateDMNode() {
+	cs, err := discordState.Cabinet.PrivateChannels()
+	if err != nil {
+		slog.Error("failed to get private channels", "err", err)
+	}
+
+	ind := ningen.ChannelRead
+	var mentions int
+	for _, c := range cs {
+		ind = max(ind, discordState.ChannelIsUnread(c.ID, ningen.UnreadOpts{}))
+		mentions += mentionCount(c.ID)
+	}
+
+	gt.setNodeState(gt.dmNode, "Direct Messages", gt.cfg.Theme.GuildsTree.PrivateChannelColor, ind, mentions, false)
+}
+
+// Updates the nodes of the channel and of the guild (or direct messages) it is in after its read state changes.
+func (gt *GuildsTree) onReadUpdate(cID discord.ChannelID, gID discord.GuildID) {
+	root := gt.GetRoot()
+	if n := findNode(root, cID); n != nil {
+		if c, err := discordState.Cabinet.Channel(cID); err == nil {
+			gt.updateChannelNode(n, *c)
+		}
+	}
+
+	layout.mentionsInbox.onUpdate()
+
+	if !gID.IsValid() {
+		if gt.dmNode != nil {
+			gt.updateDMNode()
+		}
+
+		return
+	}
+
+	if n := findNode(root, gID); n != nil {
+		if g, err := discordState.Cabinet.Guild(gID); err == nil {
+			gt.updateGuildNode(n, *g)
+		}
+	}
+}
+
+// Updates the guild node and all of its channel nodes after its notification settings change.
+func (gt *GuildsTree) onGuildSettingsUpdate(gID discord.GuildID) {
+	n := findNode(gt.GetRoot(), gID)
+	if n == nil {
+		return
+	}
+
+	if g, err := discordState.Cabinet.Guild(gID); err == nil {
+		gt.updateGuildNode(n, *g)
+	}
+
+	n.Walk(func(node, _ *tview.TreeNode) bool {
+		if cID, ok := node.GetReference().(discord.ChannelID); ok {
+			if c, err := discordState.Cabinet.Channel(cID); err == nil {
+				gt.updateChannelNode(node, *c)
+			}
+		}
+
+		return true
+	})
+}
+
 func (gt *GuildsTree) channelToString(c discord.Channel) string {
 	var s string
 	switch c.Type {
@@ -109,6 +240,8 @@ func (gt *GuildsTree) channelToString(c discord.Channel) string {
 		s = "󱃁 " + c.Name
 	case discord.GuildForum:
 		s = "󰠢 " + c.Name
+	case discord.GuildPublicThread, discord.GuildPrivateThread, discord.GuildAnnouncementThread:
+		s = "󰅺 " + c.Name
 	default:
 		s = c.Name
 	}
@@ -118,7 +251,13 @@ func (gt *GuildsTree) channelToString(c discord.Channel) string {
 
 func (gt *GuildsTree) createChannelNode(n *tview.TreeNode, c discord.Channel) *tview.TreeNode {
 	if c.Type != discord.DirectMessage && c.Type != discord.GroupDM {
-		ps, err := discordState.Permissions(c.ID, discordState.Ready().User.ID)
+		// Threads inherit the permissions of their parent channel.
+		pID := c.ID
+		if isThread(c) {
+			pID = c.ParentID
+		}
+
+		ps, err := discordState.Permissions(pID, discordState.Ready().User.ID)
 		if err != nil {
 			slog.Error("failed to get permissions", "err", err, "channel_id", c.ID)
 			return nil
@@ -131,12 +270,32 @@ func (gt *GuildsTree) createChannelNode(n *tview.TreeNode, c discord.Channel) *t
 
 	channelNode := tview.NewTreeNode(gt.channelToString(c))
 	channelNode.SetReference(c.ID)
-	channelNode.SetColor(tcell.GetColor(gt.cfg.Theme.GuildsTree.ChannelColor))
+	gt.updateChannelNode(channelNode, c)
 	n.AddChild(channelNode)
 	return channelNode
 }
 
+func isThread(c discord.Channel) bool {
+	switch c.Type {
+	case discord.GuildPublicThread, discord.GuildPrivateThread, discord.GuildAnnouncementThread:
+		return true
+	}
+
+	return false
+}
+
 func (gt *GuildsTree) createChannelNodes(n *tview.TreeNode, cs []discord.Channel) {
+	// Threads are added under their parent channel once all of the other channels are.
+	var threads []discord.Channel
+	cs = slices.DeleteFunc(cs, func(c discord.Channel) bool {
+		if isThread(c) {
+			threads = append(threads, c)
+			return true
+		}
+
+		return false
+	})
+
 	var orphanChs []discord.Channel
 	for _, ch := range cs {
 		if ch.Type != discord.GuildCategory && !ch.ParentID.IsValid() {
@@ -177,46 +336,206 @@ PARENT_CHANNELS:
 			}
 		}
 	}
+
+	for _, t := range threads {
+		if t.ThreadMetadata != nil && t.ThreadMetadata.Archived {
+			continue
+		}
+
+		if parent := findNode(n, t.ParentID); parent != nil {
+			gt.createChannelNode(parent, t)
+		}
+	}
+}
+
+// Reports whether the children of the node are threads rather than the channels of a guild or category.
+func hasThreads(n *tview.TreeNode) bool {
+	cID, ok := n.GetReference().(discord.ChannelID)
+	if !ok {
+		return false
+	}
+
+	c, err := discordState.Cabinet.Channel(cID)
+	return err == nil && c.Type != discord.GuildCategory
+}
+
+// Returns the first node under n (n included) with the given reference.
+func findNode(n *tview.TreeNode, ref any) *tview.TreeNode {
+	var found *tview.TreeNode
+	n.Walk(func(node, _ *tview.TreeNode) bool {
+		if node.GetReference() == ref {
+			found = node
+			return false
+		}
+
+		return true
+	})
+
+	return found
+}
+
+// Selects the node of the channel like a user would, creating the nodes of its guild and parent channel first if needed.
+func (gt *GuildsTree) selectChannel(cID discord.ChannelID) {
+	c, err := discordState.Channel(cID)
+	if err != nil {
+		slog.Error("failed to get channel", "err", err, "channel_id", cID)
+		return
+	}
+
+	root := gt.GetRoot()
+	node := findNode(root, c.ID)
+	if node == nil {
+		parent := gt.dmNode
+		if c.GuildID.IsValid() {
+			parent = findNode(root, c.GuildID)
+		}
+
+		if parent == nil {
+			slog.Error("failed to find parent node", "channel_id", c.ID)
+			return
+		}
+
+		if len(parent.GetChildren()) == 0 {
+			gt.createChildNodes(parent)
+		}
+
+		// Threads that are not active yet (or anymore) have no node of their own.
+		if node = findNode(parent, c.ID); node == nil && isThread(*c) {
+			if pNode := findNode(parent, c.ParentID); pNode != nil {
+				node = gt.createChannelNode(pNode, *c)
+			}
+		}
+
+		if node == nil {
+			slog.Error("failed to find channel node", "channel_id", c.ID)
+			return
+		}
+	}
+
+	for _, n := range gt.GetPath(node) {
+		n.Expand()
+	}
+
+	gt.SetCurrentNode(node)
+	gt.open(node)
+}
+
+// Selects the node of the guild, creating its channel nodes if needed, and focuses the guilds tree.
+func (gt *GuildsTree) selectGuild(gID discord.GuildID) {
+	node := findNode(gt.GetRoot(), gID)
+	if node == nil {
+		slog.Error("failed to find guild node", "guild_id", gID)
+		return
+	}
+
+	for _, n := range gt.GetPath(node) {
+		n.Expand()
+	}
+
+	if len(node.GetChildren()) == 0 {
+		gt.createChildNodes(node)
+	}
+
+	gt.SetCurrentNode(node)
+	gt.app.SetFocus(gt)
+}
+
+// Creates the node of the thread, or updates it if the thread was renamed.
+func (gt *GuildsTree) onThreadCreate(t discord.Channel) {
+	if n := findNode(gt.GetRoot(), t.ID); n != nil {
+		gt.updateChannelNode(n, t)
+		return
+	}
+
+	if parent := findNode(gt.GetRoot(), t.ParentID); parent != nil {
+		gt.createChannelNode(parent, t)
+	}
+}
+
+func (gt *GuildsTree) onThreadDelete(tID discord.ChannelID) {
+	gt.GetRoot().Walk(func(node, parent *tview.TreeNode) bool {
+		if node.GetReference() == tID && parent != nil {
+			parent.RemoveChild(node)
+			return false
+		}
+
+		return true
+	})
 }
 
 func (gt *GuildsTree) onSelected(n *tview.TreeNode) {
+	if len(n.GetChildren()) != 0 {
+		n.SetExpanded(!n.IsExpanded())
+	}
+
+	gt.open(n)
+}
+
+// Opens what the node refers to without expanding or collapsing it. Nodes with children are not opened, except for channels with threads.
+func (gt *GuildsTree) open(n *tview.TreeNode) {
 	gt.selectedChannelID = 0
 
 	layout.messagesText.reset()
 	layout.messageInput.reset()
+	layout.typingIndicator.reset()
+	layout.channelHeader.reset()
 
-	if len(n.GetChildren()) != 0 {
-		n.SetExpanded(!n.IsExpanded())
+	if len(n.GetChildren()) != 0 && !hasThreads(n) {
 		return
 	}
 
 	switch ref := n.GetReference().(type) {
+	case mentionsNodeRef:
+		layout.mentionsInbox.show()
 	case discord.GuildID:
-		cs, err := discordState.Cabinet.Channels(ref)
+		gt.createChildNodes(n)
+	case discord.ChannelID:
+		c, err := discordState.Cabinet.Channel(ref)
 		if err != nil {
-			slog.Error("failed to get channels", "err", err, "guild_id", ref)
+			slog.Error("failed to get channel", "channel_id", ref)
 			return
 		}
 
-		sort.Slice(cs, func(i, j int) bool {
-			return cs[i].Position < cs[j].Position
-		})
+		layout.memberList.setChannel(*c)
 
-		gt.createChannelNodes(n, cs)
-	case discord.ChannelID:
+		if c.Type == discord.GuildForum {
+			layout.forumView.load(*c)
+			layout.right.SwitchToPage(forumPageName)
+			gt.app.SetFocus(layout.forumView)
+			return
+		}
+
+		layout.right.SwitchToPage(messagesPageName)
+		layout.channelHeader.setChannel(*c)
+		layout.messageInput.setChannel(*c)
 		layout.messagesText.drawMsgs(ref)
 		layout.messagesText.ScrollToEnd()
+		layout.messagesText.SetTitle(gt.channelToString(*c))
 
-		c, err := discordState.Cabinet.Channel(ref)
+		gt.selectedChannelID = ref
+		gt.recents.add(ref)
+		layout.messagesText.ackLatest()
+		gt.app.SetFocus(layout.messageInput)
+	case nil: // Direct messages
+		gt.createChildNodes(n)
+	}
+}
+
+// Creates the channel nodes of a guild or of the direct messages. They are created when the node is first needed rather than all at once.
+func (gt *GuildsTree) createChildNodes(n *tview.TreeNode) {
+	switch ref := n.GetReference().(type) {
+	case discord.GuildID:
+		cs, err := discordState.Cabinet.Channels(ref)
 		if err != nil {
-			slog.Error("failed to get channel", "channel_id", ref)
+			slog.Error("failed to get channels", "err", err, "guild_id", ref)
 			return
 		}
 
-		layout.messagesText.SetTitle(gt.channelToString(*c))
+		sort.Slice(cs, func(i, j int) bool {
+			return cs[i].Position < cs[j].Position
+		})
 
-		gt.selectedChannelID = ref
-		gt.app.SetFocus(layout.messageInput)
+		gt.createChannelNodes(n, cs)
 	case nil: // Direct messages
 		cs, err := discordState.PrivateChannels()
 		if err != nil {
@@ -243,7 +562,50 @@ func (gt *GuildsTree) onInputCapture(event *tcell.EventKey) *tcell.EventKey {
 
 	case gt.cfg.Keys.GuildsTree.SelectCurrent:
 		return tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	case gt.cfg.Keys.GuildsTree.MarkRead:
+		if n := gt.GetCurrentNode(); n != nil {
+			discordState.markRead(gt.nodeChannels(n))
+		}
+	case gt.cfg.Keys.GuildsTree.MarkAllRead:
+		discordState.markRead(gt.nodeChannels(gt.GetRoot()))
 	}
 
 	return nil
 }
+
+// Returns the channels of the node and of everything under it, including the channels of guilds and direct messages whose nodes have not been created yet.
+func (gt *GuildsTree) nodeChannels(n *tview.TreeNode) []discord.ChannelID {
+	var cIDs []discord.ChannelID
+	n.Walk(func(node, _ *tview.TreeNode) bool {
+		var cs []discord.Channel
+		var err error
+		switch ref := node.GetReference().(type) {
+		case discord.GuildID:
+			cs, err = discordState.Cabinet.Channels(ref)
+		case discord.ChannelID:
+			cIDs = append(cIDs, ref)
+			return true
+		default:
+			if node != gt.dmNode {
+				return true
+			}
+
+			cs, err = discordState.Cabinet.PrivateChannels()
+		}
+
+		if err != nil {
+			slog.Error("failed to get channels", "err", err, "reference", node.GetReference())
+		}
+
+		for _, c := range cs {
+			if !slices.Contains(cIDs, c.ID) {
+				cIDs = append(cIDs, c.ID)
+			}
+		}
+
+		// The channel nodes under it have been collected already.
+		return false
+	})
+
+	return cIDs
+}
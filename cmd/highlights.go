@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"log/slog"
+	"slices"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/0xJWLabs/discordo/internal/highlight"
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+// Tracks the messages that match the highlight rules, which count as mentions until their channel is read.
+type highlights struct {
+	matcher *highlight.Matcher
+	// The highlighted messages that were received, by channel.
+	received map[discord.ChannelID][]discord.MessageID
+}
+
+func newHighlights(cfg *config.Config) *highlights {
+	matcher, err := highlight.New(cfg.Highlights)
+	if err != nil {
+		slog.Error("failed to compile highlights", "err", err)
+	}
+
+	return &highlights{
+		matcher:  matcher,
+		received: make(map[discord.ChannelID][]discord.MessageID),
+	}
+}
+
+// Reports whether the message of another user matches any of the highlight rules.
+func (h *highlights) matches(m discord.Message) bool {
+	if m.Author.ID == discordState.Ready().User.ID {
+		return false
+	}
+
+	var parentID discord.ChannelID
+	if c, err := discordState.Cabinet.Channel(m.ChannelID); err == nil {
+		parentID = c.ParentID
+	}
+
+	return h.matcher.Match(m.GuildID, m.ChannelID, parentID, m.Content)
+}
+
+func (h *highlights) onMessageCreate(m discord.Message) {
+	// Mentions of the user are already counted by the read state.
+	if kind := mentionKindOf(m); kind != mentionHighlight {
+		return
+	}
+
+	h.received[m.ChannelID] = append(h.received[m.ChannelID], m.ID)
+	layout.guildsTree.onReadUpdate(m.ChannelID, m.GuildID)
+}
+
+// Returns the number of highlighted messages in the channel that have not been read yet.
+func (h *highlights) unreadCount(cID discord.ChannelID) int {
+	ids := h.received[cID]
+	if len(ids) == 0 {
+		return 0
+	}
+
+	if rs := discordState.ReadState.ReadState(cID); rs != nil {
+		ids = slices.DeleteFunc(ids, func(id discord.MessageID) bool {
+			return id <= rs.LastMessageID
+		})
+		h.received[cID] = ids
+	}
+
+	return len(ids)
+}
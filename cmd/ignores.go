@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/0xJWLabs/discordo/internal/ignore"
+	"github.com/0xJWLabs/tview"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/gdamore/tcell/v2"
+)
+
+const ignoreFormPageName = "ignore_form"
+
+// What an ignore rule that is edited from the form matches on.
+type ignoreKind string
+
+const (
+	ignoreUser    ignoreKind = "User"
+	ignoreRole    ignoreKind = "Role"
+	ignoreBots    ignoreKind = "Bots"
+	ignoreContent ignoreKind = "Content"
+	ignoreChannel ignoreKind = "Channel"
+)
+
+var ignoreKinds = []ignoreKind{ignoreUser, ignoreRole, ignoreBots, ignoreContent, ignoreChannel}
+
+// The client-side ignore rules, which go beyond the users blocked on Discord.
+type ignores struct {
+	list *ignore.List
+	// The collapsed messages that were expanded.
+	expanded map[discord.MessageID]bool
+}
+
+func newIgnores() *ignores {
+	path := filepath.Join(config.Dir(), "ignore.toml")
+	list, err := ignore.Load(path)
+	if err != nil {
+		slog.Error("failed to load ignore rules", "err", err, "path", path)
+	}
+
+	return &ignores{
+		list:     list,
+		expanded: make(map[discord.MessageID]bool),
+	}
+}
+
+// Returns the action of the first rule that matches the message, or an empty string if none does.
+func (ig *ignores) action(m discord.Message) ignore.Action {
+	var roleIDs []discord.RoleID
+	if member := guildMember(m.GuildID, m.Author.ID, nil); member != nil {
+		roleIDs = member.RoleIDs
+	}
+
+	var parentID discord.ChannelID
+	if c, err := discordState.Cabinet.Channel(m.ChannelID); err == nil {
+		parentID = c.ParentID
+	}
+
+	return ig.list.Match(m, roleIDs, parentID)
+}
+
+// Reports whether the message is collapsed by a rule and has not been expanded.
+func (ig *ignores) collapsed(m discord.Message) bool {
+	return ig.action(m) == ignore.Collapse && !ig.expanded[m.ID]
+}
+
+func (ig *ignores) toggleExpanded(mID discord.MessageID) {
+	ig.expanded[mID] = !ig.expanded[mID]
+}
+
+// Builds the rule of the kind from the value entered in the form.
+func ignoreRule(kind ignoreKind, value string, action ignore.Action) (ignore.Rule, error) {
+	rule := ignore.Rule{Action: action}
+	if kind == ignoreBots {
+		rule.Bots = true
+		return rule, nil
+	}
+
+	if value == "" {
+		return rule, fmt.Errorf("no value for %s", strings.ToLower(string(kind)))
+	}
+
+	if kind == ignoreContent {
+		if _, err := regexp.Compile(value); err != nil {
+			return rule, err
+		}
+
+		rule.Pattern = value
+		return rule, nil
+	}
+
+	id, err := discord.ParseSnowflake(value)
+	if err != nil {
+		return rule, fmt.Errorf("invalid %s ID %q: %w", strings.ToLower(string(kind)), value, err)
+	}
+
+	switch kind {
+	case ignoreUser:
+		rule.UserID = discord.UserID(id)
+	case ignoreRole:
+		rule.RoleID = discord.RoleID(id)
+	case ignoreChannel:
+		rule.ChannelID = discord.ChannelID(id)
+	}
+
+	return rule, nil
+}
+
+// Shows a form to add, change or remove the ignore rule of the user, or of one of their roles, the channel, their content or bots.
+func showIgnoreForm(cfg *config.Config, gID discord.GuildID, cID discord.ChannelID, u discord.User) {
+	// The values the value field is prefilled with for each kind.
+	values := map[ignoreKind]string{
+		ignoreUser: u.ID.String(),
+	}
+
+	if cID.IsValid() {
+		values[ignoreChannel] = cID.String()
+	}
+
+	if member := guildMember(gID, u.ID, nil); member != nil && len(member.RoleIDs) > 0 {
+		values[ignoreRole] = member.RoleIDs[0].String()
+	}
+
+	kinds := make([]string, len(ignoreKinds))
+	for i, k := range ignoreKinds {
+		kinds[i] = string(k)
+	}
+
+	actions := make([]string, len(ignore.Actions))
+	for i, a := range ignore.Actions {
+		actions[i] = string(a)
+	}
+
+	form := tview.NewForm()
+	form.AddDropDown("Match", kinds, -1, nil)
+	form.AddInputField("Value", "", 0, nil, nil)
+	form.AddDropDown("Action", actions, 1, nil)
+
+	kindDropDown := form.GetFormItem(0).(*tview.DropDown)
+	valueField := form.GetFormItem(1).(*tview.InputField)
+	actionDropDown := form.GetFormItem(2).(*tview.DropDown)
+
+	current := func() (ignore.Rule, error) {
+		_, kind := kindDropDown.GetCurrentOption()
+		_, action := actionDropDown.GetCurrentOption()
+		return ignoreRule(ignoreKind(kind), strings.TrimSpace(valueField.GetText()), ignore.Action(action))
+	}
+
+	// Show the action of the existing rule of the kind.
+	kindDropDown.SetSelectedFunc(func(kind string, _ int) {
+		valueField.SetText(values[ignoreKind(kind)])
+		if rule, err := current(); err == nil {
+			if existing, ok := layout.ignores.list.Find(rule); ok {
+				actionDropDown.SetCurrentOption(slices.Index(ignore.Actions, existing.Action))
+			}
+		}
+	})
+	kindDropDown.SetCurrentOption(0)
+
+	save := func(remove bool) {
+		rule, err := current()
+		if err != nil {
+			slog.Error("failed to parse ignore rule", "err", err)
+			return
+		}
+
+		layout.hideModal(ignoreFormPageName)
+
+		list := layout.ignores.list
+		if remove {
+			list.Remove(rule)
+		} else if err := list.Set(rule); err != nil {
+			slog.Error("failed to compile ignore rules", "err", err)
+		}
+
+		if err := list.Save(); err != nil {
+			slog.Error("failed to save ignore rules", "err", err)
+		}
+
+		layout.messagesText.onIgnoresChange()
+	}
+
+	form.AddButton("Save", func() { save(false) })
+	form.AddButton("Remove", func() { save(true) })
+	form.AddButton("Cancel", func() {
+		layout.hideModal(ignoreFormPageName)
+	})
+	form.SetCancelFunc(func() {
+		layout.hideModal(ignoreFormPageName)
+	})
+
+	form.SetBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+	form.SetFieldBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+
+	form.SetTitle("Ignore " + u.DisplayOrUsername())
+	form.SetTitleColor(tcell.GetColor(cfg.Theme.TitleColor))
+	form.SetTitleAlign(tview.AlignLeft)
+	form.SetTitlePadding(1, 1)
+	form.SetBorder(cfg.Theme.Border)
+	form.SetBorderColor(tcell.GetColor(cfg.Theme.FocusBorderColor))
+
+	layout.showModal(ignoreFormPageName, form, 60, 11)
+}
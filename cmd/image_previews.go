@@ -0,0 +1,364 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/0xJWLabs/discordo/internal/graphics"
+	"github.com/0xJWLabs/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+const (
+	// The first line of a preview drawn with a graphics protocol holds a single character from the supplementary private use area that identifies it, so that the preview can be found on screen after drawing.
+	previewMarkerBase = 0xF0000
+	// The highest ID whose marker is still in the supplementary private use area, which ends at U+FFFFD.
+	previewMaxID = 0xFFFD
+	// The widest image that is requested from the media proxy, in pixels.
+	previewMaxPixelWidth = 800
+
+	// The height of a cell in pixels that previews are not scaled up beyond.
+	previewCellHeight = 16
+)
+
+// Previews drawn with a graphics protocol are identified by their URL and size.
+type previewKey struct {
+	url        string
+	cols, rows int
+}
+
+type previewPlacement struct {
+	id   uint32
+	x, y int
+}
+
+type imagePreviews struct {
+	cfg *config.Config
+	app *tview.Application
+	// Empty if previews are disabled.
+	protocol graphics.Protocol
+	cacheDir string
+
+	// The decoded images by URL, or nil if they could not be loaded.
+	images   map[string]image.Image
+	fetching map[string]bool
+
+	// The previews drawn with a graphics protocol, indexed by their ID minus one.
+	keys        []previewKey
+	ids         map[previewKey]uint32
+	transmitted map[uint32]bool
+	sixels      map[uint32][]byte
+	// Whether the images transmitted to the terminal are freed before the next ones are placed, since their IDs are reused.
+	freeTransmitted bool
+	// Describes what was placed on screen by the last draw, to only place the images again when something changed.
+	lastPlaced uint64
+}
+
+func newImagePreviews(app *tview.Application, cfg *config.Config) *imagePreviews {
+	ip := &imagePreviews{
+		cfg: cfg,
+		app: app,
+
+		images:   make(map[string]image.Image),
+		fetching: make(map[string]bool),
+
+		ids:         make(map[previewKey]uint32),
+		transmitted: make(map[uint32]bool),
+		sixels:      make(map[uint32][]byte),
+	}
+
+	if !cfg.ImagePreviews.Enabled || slices.Contains(cfg.ImagePreviews.DisabledTerminals, graphics.Terminal()) {
+		return ip
+	}
+
+	ip.protocol = graphics.Protocol(cfg.ImagePreviews.Protocol)
+	if cfg.ImagePreviews.Protocol == "auto" {
+		ip.protocol = graphics.Detect()
+	}
+
+	if dir, err := os.UserCacheDir(); err == nil {
+		ip.cacheDir = filepath.Join(dir, config.Name, "previews")
+	} else {
+		slog.Error("failed to get user cache directory", "err", err)
+	}
+
+	return ip
+}
+
+// Forgets the images and the previews of the previous channel, so that the IDs are reused for the next one.
+func (ip *imagePreviews) reset() {
+	clear(ip.images)
+	ip.keys = nil
+	clear(ip.ids)
+	clear(ip.sixels)
+	if len(ip.transmitted) > 0 {
+		clear(ip.transmitted)
+		ip.freeTransmitted = true
+	}
+	ip.lastPlaced = 0
+}
+
+// Returns the number of columns and rows the preview of an image of the size takes up, keeping its aspect ratio. Cells are about twice as tall as they are wide.
+func (ip *imagePreviews) size(width, height uint, maxCols int) (int, int) {
+	if width == 0 || height == 0 || maxCols <= 0 {
+		return 0, 0
+	}
+
+	ratio := float64(width) / float64(height)
+	rows := min(ip.cfg.ImagePreviews.MaxHeight, int(height+previewCellHeight-1)/previewCellHeight)
+	cols := int(float64(rows)*ratio*2 + 0.5)
+	if cols > maxCols {
+		cols = maxCols
+		rows = int(float64(cols)/ratio/2 + 0.5)
+	}
+
+	return max(cols, 1), max(rows, 1)
+}
+
+// Writes the preview of the image on the lines after the current one, or reserves the lines for it while it is loading.
+func (ip *imagePreviews) write(w io.Writer, proxyURL string, width, height uint, maxCols int) {
+	if ip.protocol == "" || proxyURL == "" {
+		return
+	}
+
+	cols, rows := ip.size(width, height, maxCols)
+	if cols == 0 {
+		return
+	}
+
+	img, ok := ip.images[proxyURL]
+	if ok && img == nil {
+		return
+	}
+
+	if !ok {
+		ip.fetch(proxyURL, width, height)
+	}
+
+	switch ip.protocol {
+	case graphics.HalfBlock:
+		if img == nil {
+			fmt.Fprint(w, strings.Repeat("\n", rows))
+			return
+		}
+
+		for _, line := range graphics.HalfBlocks(img, cols, rows) {
+			fmt.Fprintf(w, "\n%s", line)
+		}
+	default:
+		id, ok := ip.id(previewKey{url: proxyURL, cols: cols, rows: rows})
+		if !ok {
+			return
+		}
+
+		fmt.Fprintf(w, "\n[::d]%c[::-]%s", rune(previewMarkerBase+id), strings.Repeat("\n", rows-1))
+	}
+}
+
+// Returns the ID of the preview, or false if the channel has more previews than there are markers.
+func (ip *imagePreviews) id(key previewKey) (uint32, bool) {
+	if id, ok := ip.ids[key]; ok {
+		return id, true
+	}
+
+	if len(ip.keys) >= previewMaxID {
+		return 0, false
+	}
+
+	ip.keys = append(ip.keys, key)
+	id := uint32(len(ip.keys))
+	ip.ids[key] = id
+	return id, true
+}
+
+func (ip *imagePreviews) fetch(proxyURL string, width, height uint) {
+	if ip.fetching[proxyURL] {
+		return
+	}
+
+	ip.fetching[proxyURL] = true
+	go func() {
+		img, err := ip.load(proxyURL, width, height)
+		ip.app.QueueUpdateDraw(func() {
+			delete(ip.fetching, proxyURL)
+			if err != nil {
+				slog.Error("failed to load image preview", "err", err, "url", proxyURL)
+			}
+
+			ip.images[proxyURL] = img
+			layout.messagesText.onPreviewLoaded(proxyURL)
+		})
+	}()
+}
+
+// Loads the image from the disk cache, or requests it from the media proxy as PNG and caches it.
+func (ip *imagePreviews) load(proxyURL string, width, height uint) (image.Image, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// The query of attachment URLs holds a signature that changes when they are refreshed, so it is left out of the cache key.
+	sum := sha256.Sum256([]byte(u.Host + u.Path))
+	path := filepath.Join(ip.cacheDir, hex.EncodeToString(sum[:])+".png")
+	if b, err := os.ReadFile(path); err == nil {
+		img, _, err := image.Decode(bytes.NewReader(b))
+		return img, err
+	}
+
+	q := u.Query()
+	q.Set("format", "png")
+	if width > previewMaxPixelWidth {
+		q.Set("width", strconv.Itoa(previewMaxPixelWidth))
+		q.Set("height", strconv.Itoa(int(height*previewMaxPixelWidth/width)))
+	}
+	u.RawQuery = q.Encode()
+
+	resp, err := http.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	if ip.cacheDir != "" {
+		if err := os.MkdirAll(ip.cacheDir, os.ModePerm); err != nil {
+			slog.Error("failed to create preview cache directory", "err", err, "dir", ip.cacheDir)
+		} else if err := os.WriteFile(path, b, 0o644); err != nil {
+			slog.Error("failed to cache image preview", "err", err, "path", path)
+		}
+	}
+
+	return img, nil
+}
+
+// Finds the previews that are fully visible in the rectangle after drawing and places their images over them with the graphics protocol.
+func (ip *imagePreviews) place(screen tcell.Screen, visible bool, x0, y0, width, height int) {
+	if ip.protocol != graphics.Kitty && ip.protocol != graphics.Sixel {
+		return
+	}
+
+	tty, ok := screen.Tty()
+	if !ok {
+		return
+	}
+
+	var placements []previewPlacement
+	h := fnv.New64a()
+	if visible {
+		for y := y0; y < y0+height; y++ {
+			for x := x0; x < x0+width; x++ {
+				r, _, style, _ := screen.GetContent(x, y)
+				// Images drawn with sixels are overwritten by changes of the cells below them.
+				if ip.protocol == graphics.Sixel {
+					fg, bg, attrs := style.Decompose()
+					fmt.Fprint(h, r, fg, bg, attrs)
+				}
+
+				id := uint32(r - previewMarkerBase)
+				if r < previewMarkerBase || int(id) > len(ip.keys) || id == 0 {
+					continue
+				}
+
+				key := ip.keys[id-1]
+				if ip.images[key.url] == nil || y+key.rows > y0+height || x+key.cols > x0+width {
+					continue
+				}
+
+				placements = append(placements, previewPlacement{id: id, x: x, y: y})
+				fmt.Fprint(h, "#", id, x, y)
+			}
+		}
+	}
+
+	sum := h.Sum64()
+	if sum == ip.lastPlaced {
+		return
+	}
+	ip.lastPlaced = sum
+
+	var buf bytes.Buffer
+	switch {
+	case ip.protocol == graphics.Kitty && ip.freeTransmitted:
+		_ = graphics.DeleteKitty(&buf)
+		ip.freeTransmitted = false
+	case ip.protocol == graphics.Kitty:
+		_ = graphics.ClearKitty(&buf)
+	default:
+		// Repaint every cell to erase the previously placed images.
+		screen.Sync()
+	}
+
+	for _, p := range placements {
+		key := ip.keys[p.id-1]
+		img := ip.images[key.url]
+
+		// Save the cursor and move it to the top-left cell of the preview.
+		fmt.Fprintf(&buf, "\x1b7\x1b[%d;%dH", p.y+1, p.x+1)
+		switch ip.protocol {
+		case graphics.Kitty:
+			if !ip.transmitted[p.id] {
+				if err := graphics.TransmitKitty(&buf, p.id, img); err != nil {
+					slog.Error("failed to transmit image", "err", err, "url", key.url)
+					continue
+				}
+
+				ip.transmitted[p.id] = true
+			}
+
+			_ = graphics.PlaceKitty(&buf, p.id, key.cols, key.rows)
+		case graphics.Sixel:
+			sixel, ok := ip.sixels[p.id]
+			if !ok {
+				var b bytes.Buffer
+				cw, ch := graphics.CellSize()
+				if err := graphics.WriteSixel(&b, graphics.Scale(img, key.cols*cw, key.rows*ch)); err != nil {
+					slog.Error("failed to encode image", "err", err, "url", key.url)
+					continue
+				}
+
+				sixel = b.Bytes()
+				ip.sixels[p.id] = sixel
+			}
+
+			buf.Write(sixel)
+		}
+
+		// Restore the cursor since tcell keeps track of it.
+		buf.WriteString("\x1b8")
+	}
+
+	if _, err := tty.Write(buf.Bytes()); err != nil {
+		slog.Error("failed to write images to terminal", "err", err)
+	}
+}
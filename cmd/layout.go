@@ -9,31 +9,101 @@ import (
 	"github.com/zalando/go-keyring"
 )
 
+const (
+	mainPageName     = "main"
+	messagesPageName = "messages"
+	forumPageName    = "forum"
+	searchPageName   = "search"
+	pinsPageName     = "pins"
+	mentionsPageName = "mentions"
+)
+
 type Layout struct {
-	cfg          *config.Config
-	app          *tview.Application
-	flex         *tview.Flex
-	guildsTree   *GuildsTree
-	messagesText *MessagesText
-	messageInput *MessageInput
+	cfg           *config.Config
+	app           *tview.Application
+	pages         *tview.Pages
+	flex          *tview.Flex
+	right         *tview.Pages
+	messages      *tview.Flex
+	guildsTree    *GuildsTree
+	messagesText  *MessagesText
+	messageInput  *MessageInput
+	channelHeader *ChannelHeader
+	forumView     *ForumView
+
+	searchResults  *SearchResults
+	pinnedMessages *PinnedMessages
+	mentionsInbox  *MentionsInbox
+
+	typingIndicator *TypingIndicator
+	memberList      *MemberList
+	downloads       *Downloads
+	notifications   *notifications
+	highlights      *highlights
+	ignores         *ignores
+
+	guildsTreeVisible bool
+	memberListVisible bool
+
+	// The primitives that had focus before each modal was shown, keyed by page name.
+	modalFocus map[string]tview.Primitive
 }
 
 func newLayout(cfg *config.Config) *Layout {
 	app := tview.NewApplication()
 	l := &Layout{
 		cfg:  cfg,
-		app:  app,
-		flex: tview.NewFlex(),
+		app:   app,
+		pages: tview.NewPages(),
+		flex:  tview.NewFlex(),
+		right: tview.NewPages(),
 
-		guildsTree:   newGuildsTree(app, cfg),
-		messagesText: newMessagesText(app, cfg),
-		messageInput: newMessageInput(app, cfg),
+		messages: tview.NewFlex(),
+
+		guildsTree:    newGuildsTree(app, cfg),
+		messagesText:  newMessagesText(app, cfg),
+		messageInput:  newMessageInput(app, cfg),
+		channelHeader: newChannelHeader(app, cfg),
+		forumView:     newForumView(app, cfg),
+
+		searchResults:  newSearchResults(app, cfg),
+		pinnedMessages: newPinnedMessages(app, cfg),
+		mentionsInbox:  newMentionsInbox(app, cfg),
+
+		typingIndicator: newTypingIndicator(app, cfg),
+		memberList:      newMemberList(app, cfg),
+		downloads:       newDownloads(app, cfg),
+		notifications:   newNotifications(app, cfg),
+		highlights:      newHighlights(cfg),
+		ignores:         newIgnores(),
+
+		guildsTreeVisible: true,
+
+		modalFocus: make(map[string]tview.Primitive),
 	}
 
+	l.messages.SetDirection(tview.FlexRow)
+	// Hidden until a channel is selected.
+	l.messages.AddItem(l.channelHeader, 0, 0, false)
+	l.messages.AddItem(l.messagesText, 0, 1, false)
+	if cfg.ShowTypingIndicator {
+		l.messages.AddItem(l.typingIndicator, 1, 0, false)
+	}
+	l.messages.AddItem(l.messageInput, 3, 1, false)
+	// Hidden until there are downloads to show.
+	l.messages.AddItem(l.downloads, 0, 0, false)
+	l.right.AddPage(messagesPageName, l.messages, true, true)
+	l.right.AddPage(forumPageName, l.forumView, true, false)
+	l.right.AddPage(searchPageName, l.searchResults, true, false)
+	l.right.AddPage(pinsPageName, l.pinnedMessages, true, false)
+	l.right.AddPage(mentionsPageName, l.mentionsInbox, true, false)
+
 	l.init()
+	l.pages.AddPage(mainPageName, l.flex, true, true)
 
 	l.app.EnableMouse(cfg.Mouse)
 	l.app.SetInputCapture(l.onAppInputCapture)
+	l.app.SetAfterDrawFunc(l.onAfterDraw)
 
 	l.flex.SetInputCapture(l.onFlexInputCapture)
 	return l
@@ -57,7 +127,7 @@ func (l *Layout) show(token string) error {
 			return err
 		}
 
-		l.app.SetRoot(l.flex, true)
+		l.app.SetRoot(l.pages, true)
 	}
 
 	return nil
@@ -74,13 +144,71 @@ func (l *Layout) run(token string) error {
 func (l *Layout) init() {
 	l.flex.Clear()
 
-	right := tview.NewFlex()
-	right.SetDirection(tview.FlexRow)
-	right.AddItem(l.messagesText, 0, 1, false)
-	right.AddItem(l.messageInput, 3, 1, false)
 	// The guilds tree is always focused first at start-up.
-	l.flex.AddItem(l.guildsTree, 0, 1, true)
-	l.flex.AddItem(right, 0, 4, false)
+	if l.guildsTreeVisible {
+		l.flex.AddItem(l.guildsTree, 0, 1, true)
+	}
+
+	width := l.cfg.Theme.MemberList.Width
+	if l.memberListVisible && l.cfg.Theme.MemberList.Position == "left" {
+		l.flex.AddItem(l.memberList, width, 0, false)
+	}
+
+	l.flex.AddItem(l.right, 0, 4, false)
+
+	if l.memberListVisible && l.cfg.Theme.MemberList.Position != "left" {
+		l.flex.AddItem(l.memberList, width, 0, false)
+	}
+}
+
+func (l *Layout) setChannelHeaderVisible(visible bool) {
+	height := 0
+	if visible {
+		height = 1
+	}
+
+	l.messages.ResizeItem(l.channelHeader, height, 0)
+}
+
+func (l *Layout) setDownloadsVisible(visible bool) {
+	height := 0
+	if visible {
+		height = 1
+	}
+
+	l.messages.ResizeItem(l.downloads, height, 0)
+}
+
+// Shows the primitive centered on top of the main layout and focuses it.
+func (l *Layout) showModal(name string, p tview.Primitive, width, height int) {
+	grid := tview.NewGrid().
+		SetColumns(0, width, 0).
+		SetRows(0, height, 0).
+		AddItem(p, 1, 1, 1, 1, 0, 0, true)
+
+	l.modalFocus[name] = l.app.GetFocus()
+	l.pages.AddPage(name, grid, true, true)
+	l.app.SetFocus(p)
+}
+
+// Removes the modal and gives the focus back to the primitive that had it before the modal was shown.
+func (l *Layout) hideModal(name string) {
+	l.pages.RemovePage(name)
+
+	if p, ok := l.modalFocus[name]; ok && p != nil {
+		delete(l.modalFocus, name)
+		l.app.SetFocus(p)
+	}
+}
+
+// Places the image previews of the messages text, unless it is hidden behind another page or a modal, and writes the escape sequences of the notifications.
+func (l *Layout) onAfterDraw(screen tcell.Screen) {
+	l.notifications.flush(screen)
+
+	front, _ := l.pages.GetFrontPage()
+	right, _ := l.right.GetFrontPage()
+	x, y, width, height := l.messagesText.GetInnerRect()
+	l.messagesText.previews.place(screen, front == mainPageName && right == messagesPageName, x, y, width, height)
 }
 
 func (l *Layout) onAppInputCapture(event *tcell.EventKey) *tcell.EventKey {
@@ -101,7 +229,18 @@ func (l *Layout) onFlexInputCapture(event *tcell.EventKey) *tcell.EventKey {
 		l.app.SetFocus(l.guildsTree)
 		return nil
 	case l.cfg.Keys.FocusMessagesText:
-		l.app.SetFocus(l.messagesText)
+		switch name, _ := l.right.GetFrontPage(); name {
+		case forumPageName:
+			l.app.SetFocus(l.forumView)
+		case searchPageName:
+			l.app.SetFocus(l.searchResults)
+		case pinsPageName:
+			l.app.SetFocus(l.pinnedMessages)
+		case mentionsPageName:
+			l.app.SetFocus(l.mentionsInbox)
+		default:
+			l.app.SetFocus(l.messagesText)
+		}
 		return nil
 	case l.cfg.Keys.FocusMessageInput:
 		l.app.SetFocus(l.messageInput)
@@ -116,16 +255,34 @@ func (l *Layout) onFlexInputCapture(event *tcell.EventKey) *tcell.EventKey {
 
 		return nil
 	case l.cfg.Keys.ToggleGuildsTree:
-		// The guilds tree is visible if the numbers of items is two.
-		if l.flex.GetItemCount() == 2 {
-			l.flex.RemoveItem(l.guildsTree)
+		l.guildsTreeVisible = !l.guildsTreeVisible
+		l.init()
 
-			if l.guildsTree.HasFocus() {
-				l.app.SetFocus(l.flex)
-			}
-		} else {
-			l.init()
+		if l.guildsTreeVisible {
 			l.app.SetFocus(l.guildsTree)
+		} else if l.guildsTree.HasFocus() {
+			l.app.SetFocus(l.flex)
+		}
+
+		return nil
+	case l.cfg.Keys.Search:
+		showSearchForm(l.cfg)
+		return nil
+	case l.cfg.Keys.GoTo:
+		showGoToPrompt(l.cfg)
+		return nil
+	case l.cfg.Keys.QuickSwitcher:
+		showQuickSwitcher(l.cfg)
+		return nil
+	case l.cfg.Keys.ToggleMemberList:
+		l.memberListVisible = !l.memberListVisible
+		l.init()
+
+		if l.memberListVisible {
+			l.memberList.load()
+			l.app.SetFocus(l.memberList)
+		} else if l.memberList.HasFocus() {
+			l.app.SetFocus(l.flex)
 		}
 
 		return nil
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/0xJWLabs/tview"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/ningen/v3/states/member"
+	"github.com/gdamore/tcell/v2"
+)
+
+// The reference of the rows of members.
+type memberListRow struct {
+	user discord.User
+	// The index of the member in the lazy member list, used to request the chunks around it.
+	index int
+}
+
+type MemberList struct {
+	*tview.Table
+	cfg *config.Config
+	app *tview.Application
+
+	channel discord.Channel
+}
+
+func newMemberList(app *tview.Application, cfg *config.Config) *MemberList {
+	ml := &MemberList{
+		Table: tview.NewTable(),
+		cfg:   cfg,
+		app:   app,
+	}
+
+	ml.SetSelectable(true, false)
+	ml.SetSelectedFunc(ml.onSelected)
+	ml.SetSelectionChangedFunc(ml.onSelectionChanged)
+	ml.SetInputCapture(ml.onInputCapture)
+	ml.SetBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+
+	ml.SetTitle("Members")
+	ml.SetTitleColor(tcell.GetColor(cfg.Theme.TitleColor))
+	ml.SetFocusTitleColor(tcell.GetColor(cfg.Theme.FocusTitleColor))
+	ml.SetTitleAlign(tview.AlignLeft)
+	ml.SetTitlePadding(1, 1)
+
+	p := cfg.Theme.BorderPadding
+	ml.SetBorder(cfg.Theme.Border)
+	ml.SetBorderColor(tcell.GetColor(cfg.Theme.BorderColor))
+	ml.SetFocusBorderColor(tcell.GetColor(cfg.Theme.FocusBorderColor))
+	ml.SetBorderPadding(p[0], p[1], p[2], p[3])
+
+	return ml
+}
+
+// Guild channels share their member list with the channels that have the same permission overwrites, and threads use the one of their parent.
+func memberListChannelID(c discord.Channel) discord.ChannelID {
+	if isThread(c) {
+		return c.ParentID
+	}
+
+	return c.ID
+}
+
+func (ml *MemberList) listChannelID() discord.ChannelID {
+	return memberListChannelID(ml.channel)
+}
+
+func (ml *MemberList) setChannel(c discord.Channel) {
+	ml.channel = c
+	if layout.memberListVisible {
+		ml.load()
+	}
+}
+
+// Subscribes to the first chunk of the member list of the channel and draws whatever is known of it already.
+func (ml *MemberList) load() {
+	if ml.channel.GuildID.IsValid() {
+		discordState.MemberState.RequestMemberList(ml.channel.GuildID, ml.listChannelID(), 0)
+	}
+
+	ml.draw()
+	ml.ScrollToBeginning()
+}
+
+func (ml *MemberList) onListUpdate(ev *gateway.GuildMemberListUpdateEvent) {
+	if !layout.memberListVisible || ev.GuildID != ml.channel.GuildID {
+		return
+	}
+
+	c, err := discordState.Cabinet.Channel(ml.listChannelID())
+	if err != nil || member.ComputeListID(c.Overwrites) != ev.ID {
+		return
+	}
+
+	ml.draw()
+}
+
+func (ml *MemberList) draw() {
+	ml.Clear()
+	if !ml.channel.ID.IsValid() {
+		return
+	}
+
+	if !ml.channel.GuildID.IsValid() {
+		ml.SetTitle("Members")
+		for _, u := range slices.Concat(ml.channel.DMRecipients, []discord.User{discordState.Ready().User}) {
+			ml.addMember(u, u.DisplayOrUsername(), ml.cfg.Theme.MemberList.MemberColor, presenceStatus(0, u.ID), -1)
+		}
+
+		return
+	}
+
+	gID := ml.channel.GuildID
+	list, err := discordState.MemberState.GetMemberList(gID, ml.listChannelID())
+	if err != nil {
+		// The list has not been received yet.
+		return
+	}
+
+	ml.SetTitle(fmt.Sprintf("Members (%d online)", list.OnlineCount()))
+	list.ViewItems(func(items []gateway.GuildMemberListOpItem) {
+		for i, item := range items {
+			switch {
+			case item.Group != nil:
+				text := fmt.Sprintf("[::b]%s — %d[::-]", tview.Escape(groupName(gID, item.Group.ID)), item.Group.Count)
+				ml.SetCell(ml.GetRowCount(), 0, tview.NewTableCell(text).
+					SetTextColor(tcell.GetColor(ml.cfg.Theme.MemberList.GroupColor)).
+					SetSelectable(false))
+			case item.Member != nil:
+				m := item.Member.Member
+				name := m.Nick
+				if name == "" {
+					name = m.User.DisplayOrUsername()
+				}
+
+				color := ml.cfg.Theme.MemberList.MemberColor
+				if c, ok := memberColor(gID, m); ok {
+					color = c.String()
+				}
+
+				ml.addMember(m.User, name, color, item.Member.Presence.Status, i)
+			}
+		}
+	})
+}
+
+func (ml *MemberList) addMember(u discord.User, name string, color string, status discord.Status, index int) {
+	text := fmt.Sprintf("[%s]●[-] [%s]%s[-]", ml.statusColor(status), color, tview.Escape(name))
+	ml.SetCell(ml.GetRowCount(), 0, tview.NewTableCell(text).
+		SetReference(memberListRow{user: u, index: index}).
+		SetExpansion(1))
+}
+
+func (ml *MemberList) statusColor(status discord.Status) string {
+	theme := ml.cfg.Theme.MemberList
+	switch status {
+	case discord.OnlineStatus:
+		return theme.OnlineColor
+	case discord.IdleStatus:
+		return theme.IdleColor
+	case discord.DoNotDisturbStatus:
+		return theme.DoNotDisturbColor
+	default:
+		return theme.OfflineColor
+	}
+}
+
+func presenceStatus(gID discord.GuildID, uID discord.UserID) discord.Status {
+	p, err := discordState.Cabinet.Presence(gID, uID)
+	if err != nil {
+		return discord.OfflineStatus
+	}
+
+	return p.Status
+}
+
+// Returns the name of the group of the member list, which is either a hoisted role or the online and offline members.
+func groupName(gID discord.GuildID, id string) string {
+	switch id {
+	case "online":
+		return "Online"
+	case "offline":
+		return "Offline"
+	}
+
+	rID, err := discord.ParseSnowflake(id)
+	if err != nil {
+		return id
+	}
+
+	r, err := discordState.Cabinet.Role(gID, discord.RoleID(rID))
+	if err != nil {
+		slog.Error("failed to get role", "err", err, "guild_id", gID, "role_id", rID)
+		return id
+	}
+
+	return r.Name
+}
+
+// Requests the chunks around the selected member so that the list is filled as it is scrolled through.
+func (ml *MemberList) onSelectionChanged(row, _ int) {
+	ref, ok := ml.GetCell(row, 0).GetReference().(memberListRow)
+	if !ok || ref.index == -1 {
+		return
+	}
+
+	discordState.MemberState.RequestMemberList(ml.channel.GuildID, ml.listChannelID(), member.ChunkFromIndex(ref.index))
+}
+
+func (ml *MemberList) onSelected(row, _ int) {
+	ref, ok := ml.GetCell(row, 0).GetReference().(memberListRow)
+	if !ok {
+		return
+	}
+
+	showUserProfile(ml.cfg, ml.channel.GuildID, ref.user)
+}
+
+func (ml *MemberList) onInputCapture(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Name() {
+	case ml.cfg.Keys.SelectPrevious:
+		return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+	case ml.cfg.Keys.SelectNext:
+		return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+	case ml.cfg.Keys.SelectFirst:
+		return tcell.NewEventKey(tcell.KeyHome, 0, tcell.ModNone)
+	case ml.cfg.Keys.SelectLast:
+		return tcell.NewEventKey(tcell.KeyEnd, 0, tcell.ModNone)
+	}
+
+	return event
+}
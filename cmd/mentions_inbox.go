@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/0xJWLabs/tview"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"github.com/diamondburned/ningen/v3"
+	"github.com/gdamore/tcell/v2"
+)
+
+// The number of mentions that are kept, latest first.
+const maxMentions = 100
+
+type mentionKind int
+
+const (
+	mentionUser mentionKind = iota + 1
+	mentionRole
+	mentionEveryone
+	// The message matches a highlight rule.
+	mentionHighlight
+)
+
+// The mentions that are shown, cycled through with the filter key.
+type mentionFilter int
+
+const (
+	mentionFilterAll mentionFilter = iota
+	mentionFilterUsers
+	mentionFilterRoles
+	mentionFilterEveryone
+	mentionFilterHighlights
+)
+
+var mentionFilterNames = [...]string{"all", "users", "roles", "everyone", "highlights"}
+
+type mention struct {
+	discord.Message
+	kind mentionKind
+}
+
+// The reference of the mentions node of the guilds tree.
+type mentionsNodeRef struct{}
+
+// Collects the messages that mention the current user, their roles or everyone across all channels.
+type MentionsInbox struct {
+	*tview.Table
+	cfg *config.Config
+	app *tview.Application
+
+	mentions []mention
+	filter   mentionFilter
+}
+
+func newMentionsInbox(app *tview.Application, cfg *config.Config) *MentionsInbox {
+	mi := &MentionsInbox{
+		Table: tview.NewTable(),
+		cfg:   cfg,
+		app:   app,
+	}
+
+	mi.SetSelectable(true, false)
+	mi.SetSelectedFunc(mi.onSelected)
+	mi.SetInputCapture(mi.onInputCapture)
+	mi.SetBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+
+	mi.SetTitle("Mentions")
+	mi.SetTitleColor(tcell.GetColor(cfg.Theme.TitleColor))
+	mi.SetFocusTitleColor(tcell.GetColor(cfg.Theme.FocusTitleColor))
+	mi.SetTitleAlign(tview.AlignLeft)
+	mi.SetTitlePadding(1, 1)
+
+	p := cfg.Theme.BorderPadding
+	mi.SetBorder(cfg.Theme.Border)
+	mi.SetBorderColor(tcell.GetColor(cfg.Theme.BorderColor))
+	mi.SetFocusBorderColor(tcell.GetColor(cfg.Theme.FocusBorderColor))
+	mi.SetBorderPadding(p[0], p[1], p[2], p[3])
+
+	return mi
+}
+
+// Fills the inbox with the recent mentions, which include the ones sent while the client was not running.
+func (mi *MentionsInbox) backfill() {
+	go func() {
+		var ms []discord.Message
+		err := discordState.RequestJSON(
+			&ms, "GET",
+			api.EndpointMe+"mentions",
+			httputil.WithSchema(discordState.Client, url.Values{
+				"limit":    {"50"},
+				"roles":    {"true"},
+				"everyone": {"true"},
+			}),
+		)
+		if err != nil {
+			slog.Error("failed to get recent mentions", "err", err)
+			return
+		}
+
+		mi.app.QueueUpdateDraw(func() {
+			for _, m := range ms {
+				mi.add(m)
+			}
+		})
+	}()
+}
+
+// Adds the message to the inbox if it mentions the current user.
+func (mi *MentionsInbox) onMessageCreate(m discord.Message) {
+	mi.add(m)
+}
+
+func (mi *MentionsInbox) add(m discord.Message) {
+	kind := mentionKindOf(m)
+	if kind == 0 || slices.ContainsFunc(mi.mentions, func(mn mention) bool { return mn.ID == m.ID }) {
+		return
+	}
+
+	mi.mentions = append(mi.mentions, mention{m, kind})
+	slices.SortFunc(mi.mentions, func(a, b mention) int {
+		return cmp.Compare(b.ID, a.ID)
+	})
+	mi.mentions = mi.mentions[:min(len(mi.mentions), maxMentions)]
+
+	mi.onUpdate()
+}
+
+// Returns how the message mentions the current user, or 0 if it does not. Highlighted messages count as mentions too.
+func mentionKindOf(m discord.Message) mentionKind {
+	me := discordState.Ready().User.ID
+	if m.Author.ID == me || discordState.UserIsBlocked(m.Author.ID) {
+		return 0
+	}
+
+	if slices.ContainsFunc(m.Mentions, func(u discord.GuildUser) bool { return u.ID == me }) {
+		return mentionUser
+	}
+
+	if m.GuildID.IsValid() && len(m.MentionRoleIDs) > 0 {
+		if member, err := discordState.Cabinet.Member(m.GuildID, me); err == nil {
+			for _, rID := range m.MentionRoleIDs {
+				if slices.Contains(member.RoleIDs, rID) {
+					return mentionRole
+				}
+			}
+		}
+	}
+
+	if m.MentionEveryone {
+		return mentionEveryone
+	}
+
+	if layout.highlights.matches(m) {
+		return mentionHighlight
+	}
+
+	return 0
+}
+
+// Reports whether the channel has been read up to the message.
+func mentionIsRead(m mention) bool {
+	rs := discordState.ReadState.ReadState(m.ChannelID)
+	return rs != nil && rs.LastMessageID >= m.ID
+}
+
+func (mi *MentionsInbox) unreadCount() int {
+	var n int
+	for _, m := range mi.mentions {
+		if !mentionIsRead(m) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Updates the mentions node and the inbox after the mentions or their read states change.
+func (mi *MentionsInbox) onUpdate() {
+	layout.guildsTree.updateMentionsNode()
+	if name, _ := layout.right.GetFrontPage(); name == mentionsPageName {
+		mi.draw()
+	}
+}
+
+func (mi *MentionsInbox) show() {
+	layout.right.SwitchToPage(mentionsPageName)
+	mi.draw()
+	mi.Select(0, 0)
+	mi.ScrollToBeginning()
+	mi.app.SetFocus(mi)
+}
+
+func (mi *MentionsInbox) matches(m mention) bool {
+	switch mi.filter {
+	case mentionFilterUsers:
+		return m.kind == mentionUser
+	case mentionFilterRoles:
+		return m.kind == mentionRole
+	case mentionFilterEveryone:
+		return m.kind == mentionEveryone
+	case mentionFilterHighlights:
+		return m.kind == mentionHighlight
+	}
+
+	return true
+}
+
+func (mi *MentionsInbox) draw() {
+	row, _ := mi.GetSelection()
+	mi.Clear()
+
+	mi.SetTitle(fmt.Sprintf("Mentions (%s, %d unread)", mentionFilterNames[mi.filter], mi.unreadCount()))
+
+	theme := mi.cfg.Theme.MessagesText
+	var i int
+	for _, m := range mi.mentions {
+		if !mi.matches(m) {
+			continue
+		}
+
+		channel := m.ChannelID.String()
+		if c, err := discordState.Cabinet.Channel(m.ChannelID); err == nil {
+			channel = layout.guildsTree.channelToString(*c)
+			if g, err := discordState.Cabinet.Guild(c.GuildID); err == nil {
+				channel = g.Name + " / " + channel
+			}
+		}
+
+		var attrs tcell.AttrMask
+		if !mentionIsRead(m) {
+			attrs = tcell.AttrBold
+		}
+
+		name := userName(mi.cfg, m.Author, guildMember(m.GuildID, m.Author.ID, nil))
+		content, _, _ := strings.Cut(m.Content, "\n")
+
+		mi.SetCell(i, 0, tview.NewTableCell(tview.Escape(channel)).SetReference(m).SetMaxWidth(30).SetAttributes(attrs))
+		mi.SetCell(i, 1, tview.NewTableCell(tview.Escape(name)).SetTextColor(tcell.GetColor(theme.AuthorColor)).SetMaxWidth(20).SetAttributes(attrs))
+		mi.SetCell(i, 2, tview.NewTableCell(m.Timestamp.Time().Local().Format(searchDateFormat)).SetAttributes(tcell.AttrDim))
+		mi.SetCell(i, 3, tview.NewTableCell(tview.Escape(content)).SetExpansion(1).SetAttributes(attrs))
+		i++
+	}
+
+	mi.Select(min(row, max(i-1, 0)), 0)
+}
+
+func (mi *MentionsInbox) onSelected(row, _ int) {
+	m, ok := mi.GetCell(row, 0).GetReference().(mention)
+	if !ok {
+		return
+	}
+
+	layout.right.SwitchToPage(messagesPageName)
+	layout.messagesText.jumpToMessage(m.ChannelID, m.ID)
+}
+
+func (mi *MentionsInbox) onInputCapture(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Name() {
+	case mi.cfg.Keys.SelectPrevious:
+		return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+	case mi.cfg.Keys.SelectNext:
+		return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+	case mi.cfg.Keys.SelectFirst:
+		return tcell.NewEventKey(tcell.KeyHome, 0, tcell.ModNone)
+	case mi.cfg.Keys.SelectLast:
+		return tcell.NewEventKey(tcell.KeyEnd, 0, tcell.ModNone)
+	case mi.cfg.Keys.MentionsInbox.CycleFilter:
+		mi.filter = (mi.filter + 1) % mentionFilter(len(mentionFilterNames))
+		mi.draw()
+		return nil
+	case mi.cfg.Keys.MentionsInbox.Close:
+		layout.right.SwitchToPage(messagesPageName)
+		mi.app.SetFocus(layout.messagesText)
+		return nil
+	}
+
+	return event
+}
+
+// Shows the number of unread mentions on the mentions node.
+func (gt *GuildsTree) updateMentionsNode() {
+	if gt.mentionsNode == nil {
+		return
+	}
+
+	unread := layout.mentionsInbox.unreadCount()
+	ind := ningen.ChannelRead
+	if unread > 0 {
+		ind = ningen.ChannelMentioned
+	}
+
+	gt.setNodeState(gt.mentionsNode, "Mentions", gt.cfg.Theme.GuildsTree.PrivateChannelColor, ind, unread, false)
+}
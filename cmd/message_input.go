@@ -1,27 +1,54 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/atotto/clipboard"
 	"github.com/0xJWLabs/discordo/internal/config"
 	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/diamondburned/arikawa/v3/utils/sendpart"
 	"github.com/gdamore/tcell/v2"
 	"github.com/0xJWLabs/tview"
 )
 
 const tmpFilePattern = config.Name + "_*.md"
 
+// Typing indicators last for 10 seconds, so they are re-triggered a bit before that.
+const typingThrottle = 8 * time.Second
+
 type MessageInput struct {
 	*tview.TextArea
 	cfg            *config.Config
 	app            *tview.Application
 	replyMessageID discord.MessageID
+	editMessageID  discord.MessageID
+	// When the typing indicator was last triggered in the selected channel.
+	lastTypingAt time.Time
+	// Whether the text is being set by the client rather than typed, which does not trigger the typing indicator.
+	settingText bool
+
+	// The title set by replying or editing, which the queued attachments are appended to.
+	title string
+	// The paths of the files that are uploaded along with the next message.
+	attachments []string
+	// The directory the file picker was last in.
+	attachDir string
+
+	// Why the selected channel is read-only, if it is.
+	readOnly string
+	// When the slowmode of the channel that was last sent to allows sending again.
+	slowmodeChannelID discord.ChannelID
+	slowmodeUntil     time.Time
 }
 
 func newMessageInput(app *tview.Application, cfg *config.Config) *MessageInput {
@@ -40,6 +67,7 @@ func newMessageInput(app *tview.Application, cfg *config.Config) *MessageInput {
 	})
 
 	mi.SetInputCapture(mi.onInputCapture)
+	mi.SetChangedFunc(mi.onChanged)
 	mi.SetBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
 
 	mi.SetTitleColor(tcell.GetColor(cfg.Theme.TitleColor))
@@ -58,9 +86,129 @@ func newMessageInput(app *tview.Application, cfg *config.Config) *MessageInput {
 
 func (mi *MessageInput) reset() {
 	mi.replyMessageID = 0
-	mi.SetTitle("")
-	mi.SetTitlePadding(0, 0)
-	mi.SetText("", true)
+	mi.editMessageID = 0
+	mi.lastTypingAt = time.Time{}
+	mi.attachments = nil
+	mi.setTitle("")
+	mi.setText("")
+
+	// The input stays read-only only while a read-only channel is selected.
+	if c, err := discordState.Cabinet.Channel(layout.guildsTree.selectedChannelID); err == nil {
+		mi.setChannel(*c)
+	} else {
+		mi.setReadOnly("")
+	}
+}
+
+// Replaces the text without triggering the typing indicator.
+func (mi *MessageInput) setText(text string) {
+	mi.settingText = true
+	defer func() { mi.settingText = false }()
+	mi.SetText(text, true)
+}
+
+// Sets the title of the input, followed by the names of the queued attachments.
+func (mi *MessageInput) setTitle(title string) {
+	mi.title = title
+
+	if len(mi.attachments) > 0 {
+		names := make([]string, len(mi.attachments))
+		for i, path := range mi.attachments {
+			names[i] = filepath.Base(path)
+		}
+
+		if title != "" {
+			title += " | "
+		}
+
+		title += "Attachments: " + strings.Join(names, ", ")
+	}
+
+	if remaining := mi.slowmodeRemaining(); remaining > 0 {
+		if title != "" {
+			title += " | "
+		}
+
+		// Rounded up, so that the last second is not shown as 0s.
+		title += "Slowmode: " + formatSeconds((remaining + time.Second - 1).Truncate(time.Second))
+	}
+
+	mi.SetTitle(title)
+	if title == "" {
+		mi.SetTitlePadding(0, 0)
+	} else {
+		mi.SetTitlePadding(1, 1)
+	}
+}
+
+// Makes the input read-only with an explanation if the current user cannot send messages in the channel.
+func (mi *MessageInput) setChannel(c discord.Channel) {
+	ok, reason := canSendMessages(c)
+	mi.setReadOnly(ternary(ok, "", reason))
+}
+
+// Disables the input with the reason as its placeholder, or enables it if the reason is empty.
+func (mi *MessageInput) setReadOnly(reason string) {
+	mi.readOnly = reason
+	mi.SetDisabled(reason != "")
+	mi.SetPlaceholder(reason)
+	mi.setTitle(mi.title)
+}
+
+// Returns how long the current user has to wait before sending to the selected channel again.
+func (mi *MessageInput) slowmodeRemaining() time.Duration {
+	if mi.slowmodeChannelID != layout.guildsTree.selectedChannelID {
+		return 0
+	}
+
+	return time.Until(mi.slowmodeUntil)
+}
+
+// Counts the slowmode down in the title of the input after a message is sent to the channel.
+func (mi *MessageInput) startSlowmode(c discord.Channel) {
+	until := time.Now().Add(time.Duration(c.UserRateLimit) * time.Second)
+	mi.slowmodeChannelID = c.ID
+	mi.slowmodeUntil = until
+	mi.setTitle(mi.title)
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			done := !time.Now().Before(until)
+			mi.app.QueueUpdateDraw(func() {
+				mi.setTitle(mi.title)
+			})
+
+			if done {
+				return
+			}
+		}
+	}()
+}
+
+func (mi *MessageInput) toggleAttachment(path string) {
+	if idx := slices.Index(mi.attachments, path); idx != -1 {
+		mi.attachments = slices.Delete(mi.attachments, idx, idx+1)
+	} else {
+		mi.attachments = append(mi.attachments, path)
+	}
+
+	mi.setTitle(mi.title)
+}
+
+func (mi *MessageInput) showFilePicker() {
+	dir := mi.attachDir
+	if dir == "" {
+		var err error
+		if dir, err = os.Getwd(); err != nil {
+			slog.Error("failed to get working directory", "err", err)
+			return
+		}
+	}
+
+	layout.showModal(filePickerPageName, newFilePicker(mi.cfg, dir), 60, 20)
 }
 
 func (mi *MessageInput) onInputCapture(event *tcell.EventKey) *tcell.EventKey {
@@ -74,45 +222,112 @@ func (mi *MessageInput) onInputCapture(event *tcell.EventKey) *tcell.EventKey {
 	case mi.cfg.Keys.MessageInput.Cancel:
 		mi.reset()
 		return nil
+	case mi.cfg.Keys.MessageInput.Attach:
+		// Edits only change the content, so files cannot be attached to them.
+		if mi.editMessageID == 0 {
+			mi.showFilePicker()
+		}
+		return nil
+	case mi.cfg.Keys.MessageInput.RemoveAttachment:
+		if n := len(mi.attachments); n > 0 {
+			mi.toggleAttachment(mi.attachments[n-1])
+		}
+		return nil
 	}
 
 	return event
 }
 
+// Triggers the typing indicator of the selected channel at most once per typingThrottle while text is being entered.
+func (mi *MessageInput) onChanged() {
+	cID := layout.guildsTree.selectedChannelID
+	if mi.settingText || !mi.cfg.SendTypingIndicator || !cID.IsValid() || mi.GetText() == "" || time.Since(mi.lastTypingAt) < typingThrottle {
+		return
+	}
+
+	mi.lastTypingAt = time.Now()
+	go func() {
+		if err := discordState.Typing(cID); err != nil {
+			slog.Error("failed to trigger typing indicator", "err", err, "channel_id", cID)
+		}
+	}()
+}
+
 func (mi *MessageInput) send() {
 	if !layout.guildsTree.selectedChannelID.IsValid() {
 		return
 	}
 
 	text := strings.TrimSpace(mi.GetText())
-	if text == "" {
+	if mi.editMessageID != 0 {
+		if text == "" {
+			return
+		}
+
+		mID := mi.editMessageID
+		go func() {
+			if _, err := discordState.EditMessage(layout.guildsTree.selectedChannelID, mID, text); err != nil {
+				slog.Error("failed to edit message", "err", err, "message_id", mID)
+			}
+		}()
+
+		// Keep the edited message in view instead of jumping to the end.
+		mi.reset()
+		return
+	}
+
+	if text == "" && len(mi.attachments) == 0 {
+		return
+	}
+
+	if mi.readOnly != "" {
 		return
 	}
 
+	// Discord rejects the message until the slowmode is over, so it is kept in the input.
+	if mi.slowmodeRemaining() > 0 {
+		return
+	}
+
+	data := api.SendMessageData{Content: text}
 	if mi.replyMessageID != 0 {
-		data := api.SendMessageData{
-			Content:         text,
-			Reference:       &discord.MessageReference{MessageID: mi.replyMessageID},
-			AllowedMentions: &api.AllowedMentions{RepliedUser: option.False},
+		data.Reference = &discord.MessageReference{MessageID: mi.replyMessageID}
+		data.AllowedMentions = &api.AllowedMentions{RepliedUser: option.False}
+		if strings.HasPrefix(mi.title, "[@]") {
+			data.AllowedMentions.RepliedUser = option.True
 		}
+	}
 
-		if strings.HasPrefix(mi.GetTitle(), "[@]") {
-			data.AllowedMentions.RepliedUser = option.True
+	// The attachments are opened before the input is reset, so that the message is kept if one of them cannot be.
+	for _, path := range mi.attachments {
+		f, err := os.Open(path)
+		if err != nil {
+			slog.Error("failed to open attachment", "err", err, "path", path)
+			closeFiles(data.Files)
+			mi.SetTitle(tview.Escape(fmt.Sprintf("Failed to open %s: %v", filepath.Base(path), err)))
+			mi.SetTitlePadding(1, 1)
+			return
 		}
 
-		go func() {
-			if _, err := discordState.SendMessageComplex(layout.guildsTree.selectedChannelID, data); err != nil {
-				slog.Error("failed to send message", "err", err)
-			}
-		}()
-	} else {
-		go func() {
-			if _, err := discordState.SendMessage(layout.guildsTree.selectedChannelID, text); err != nil {
-				slog.Error("failed to send message", "err", err)
-			}
-		}()
+		data.Files = append(data.Files, sendpart.File{Name: filepath.Base(path), Reader: f})
 	}
 
+	cID := layout.guildsTree.selectedChannelID
+	go func() {
+		defer closeFiles(data.Files)
+		if _, err := discordState.SendMessageComplex(cID, data); err != nil {
+			slog.Error("failed to send message", "err", err, "channel_id", cID)
+			return
+		}
+
+		// The slowmode only starts once the message was accepted.
+		mi.app.QueueUpdateDraw(func() {
+			if c, err := discordState.Cabinet.Channel(cID); err == nil && slowmodeApplies(*c) {
+				mi.startSlowmode(*c)
+			}
+		})
+	}()
+
 	mi.replyMessageID = 0
 	mi.reset()
 
@@ -120,18 +335,36 @@ func (mi *MessageInput) send() {
 	layout.messagesText.ScrollToEnd()
 }
 
+func closeFiles(files []sendpart.File) {
+	for _, f := range files {
+		if c, ok := f.Reader.(io.Closer); ok {
+			c.Close()
+		}
+	}
+}
+
 func (mi *MessageInput) editor() {
-	e := mi.cfg.Editor
+	text, err := openEditor(mi.app, mi.cfg, mi.GetText())
+	if err != nil {
+		slog.Error("failed to edit message in editor", "err", err)
+		return
+	}
+
+	mi.setText(text)
+}
+
+// Suspends the application to edit the text in the configured editor and returns the trimmed result.
+func openEditor(app *tview.Application, cfg *config.Config, text string) (string, error) {
+	e := cfg.Editor
 	if e == "default" {
 		e = os.Getenv("EDITOR")
 	}
 
 	f, err := os.CreateTemp("", tmpFilePattern)
 	if err != nil {
-		slog.Error("failed to create temporary file", "err", err)
-		return
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
 	}
-	_, _ = f.WriteString(mi.GetText())
+	_, _ = f.WriteString(text)
 	f.Close()
 
 	defer os.Remove(f.Name())
@@ -141,7 +374,7 @@ func (mi *MessageInput) editor() {
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	mi.app.Suspend(func() {
+	app.Suspend(func() {
 		err := cmd.Run()
 		if err != nil {
 			slog.Error("failed to run command", "err", err, "command", cmd)
@@ -149,11 +382,10 @@ func (mi *MessageInput) editor() {
 		}
 	})
 
-	msg, err := os.ReadFile(f.Name())
+	b, err := os.ReadFile(f.Name())
 	if err != nil {
-		slog.Error("failed to read temporary file", "err", err, "name", f.Name())
-		return
+		return "", fmt.Errorf("failed to read temporary file %s: %w", f.Name(), err)
 	}
 
-	mi.SetText(strings.TrimSpace(string(msg)), true)
+	return strings.TrimSpace(string(b)), nil
 }
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/ningen/v3/discordmd"
+)
+
+// Clicking on a message link highlights a region with this prefix followed by the channel and message IDs.
+const messageLinkRegionPrefix = "link:"
+
+// Matches the links to channels and messages of the official clients, with the guild ID or @me for direct messages.
+var messageLinkPattern = regexp.MustCompile(`^https?://(?:(?:www|ptb|canary)\.)?discord(?:app)?\.com/channels/(?:\d+|@me)/(\d+)(?:/(\d+))?/?$`)
+
+type messageLink struct {
+	channelID discord.ChannelID
+	// Zero for links to channels.
+	messageID discord.MessageID
+}
+
+// Parses a message link, or raw IDs: a message ID in the selected channel, a channel ID and a message ID, or a guild ID, a channel ID and a message ID.
+func parseMessageLink(s string) (messageLink, error) {
+	s = strings.TrimSpace(s)
+	if m := messageLinkPattern.FindStringSubmatch(s); m != nil {
+		cID, _ := discord.ParseSnowflake(m[1])
+		var mID discord.Snowflake
+		if m[2] != "" {
+			mID, _ = discord.ParseSnowflake(m[2])
+		}
+
+		return messageLink{discord.ChannelID(cID), discord.MessageID(mID)}, nil
+	}
+
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == '/' || r == '-'
+	})
+
+	ids := make([]discord.Snowflake, len(fields))
+	for i, f := range fields {
+		id, err := discord.ParseSnowflake(f)
+		if err != nil {
+			return messageLink{}, fmt.Errorf("invalid ID %q: %w", f, err)
+		}
+
+		ids[i] = id
+	}
+
+	switch len(ids) {
+	case 1:
+		return messageLink{layout.guildsTree.selectedChannelID, discord.MessageID(ids[0])}, nil
+	case 2:
+		return messageLink{discord.ChannelID(ids[0]), discord.MessageID(ids[1])}, nil
+	case 3:
+		return messageLink{discord.ChannelID(ids[1]), discord.MessageID(ids[2])}, nil
+	default:
+		return messageLink{}, fmt.Errorf("invalid message link %q", s)
+	}
+}
+
+func showGoToPrompt(cfg *config.Config) {
+	showPrompt(cfg, "Go to message link or IDs", "", func(text string) {
+		link, err := parseMessageLink(text)
+		if err != nil {
+			slog.Error("failed to parse message link", "err", err)
+			return
+		}
+
+		layout.messagesText.jumpToLink(link)
+	})
+}
+
+func (mt *MessagesText) jumpToLink(link messageLink) {
+	if !link.channelID.IsValid() {
+		return
+	}
+
+	if !link.messageID.IsValid() {
+		layout.guildsTree.selectChannel(link.channelID)
+		return
+	}
+
+	layout.right.SwitchToPage(messagesPageName)
+	mt.jumpToMessage(link.channelID, link.messageID)
+}
+
+// The writer the markdown of a message is rendered to, which tells the link tags which region to start again after a link.
+type regionWriter struct {
+	io.Writer
+	regionID discord.MessageID
+}
+
+// Renders the markdown of the message. The region with regionID is started again after the links to messages.
+func (mt *MessagesText) renderMarkdown(w io.Writer, content string, m discord.Message, regionID discord.MessageID) {
+	src := []byte(content)
+	ast := discordmd.ParseWithMessage(src, *discordState.Cabinet, &m, false)
+	mt.renderer.Render(&regionWriter{w, regionID}, src, ast)
+}
+
+// Makes the links to messages regions that jump to the message when highlighted.
+func (mt *MessagesText) linkTags(w io.Writer, url string) (string, string) {
+	rw, ok := w.(*regionWriter)
+	if !ok || !messageLinkPattern.MatchString(url) {
+		return "", ""
+	}
+
+	link, _ := parseMessageLink(url)
+	return fmt.Sprintf(`["%s%s/%s"]`, messageLinkRegionPrefix, link.channelID, link.messageID), fmt.Sprintf(`["%s"]`, rw.regionID)
+}
+
+// Parses the region of a message link back into the link.
+func parseMessageLinkRegion(region string) (messageLink, bool) {
+	ids, ok := strings.CutPrefix(region, messageLinkRegionPrefix)
+	if !ok {
+		return messageLink{}, false
+	}
+
+	link, err := parseMessageLink(ids)
+	return link, err == nil
+}
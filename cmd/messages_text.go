@@ -5,26 +5,58 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"regexp"
 	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/0xJWLabs/discordo/internal/ignore"
 	"github.com/0xJWLabs/discordo/internal/markdown"
 	"github.com/0xJWLabs/tview"
 	"github.com/atotto/clipboard"
+	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/discord"
-	"github.com/diamondburned/ningen/v3/discordmd"
+	"github.com/diamondburned/arikawa/v3/state/store/defaultstore"
 	"github.com/gdamore/tcell/v2"
 	"github.com/skratchdot/open-golang/open"
 	"github.com/yuin/goldmark/renderer"
 )
 
+// Clicking on the thread line of a message highlights a region with this prefix instead of the message ID.
+const threadRegionPrefix = "thread:"
+
+// Region tags are stripped before measuring text since tview.WordWrap only understands style tags.
+var regionTagPattern = regexp.MustCompile(`\["[^"]*"\]`)
+
+// Matches the style tags that set the foreground color, which is captured.
+var foregroundTagPattern = regexp.MustCompile(`\[([a-zA-Z]+|#[0-9a-fA-F]{6}|-)(?::[^\[\]"]*)?\]`)
+
+// Matches the style tags that reset the attributes, such as [::-] and [-:-:-].
+var attributesResetPattern = regexp.MustCompile(`\[[a-zA-Z0-9#-]*:[a-zA-Z0-9#-]*:-[^\[\]"]*\]`)
+
 type MessagesText struct {
 	*tview.TextView
 	cfg               *config.Config
 	app               *tview.Application
 	selectedMessageID discord.MessageID
+
+	// The loaded history of the selected channel, ordered from latest to oldest. Unlike the cabinet, it is not capped.
+	messages         []discord.Message
+	fetchingOlder    bool
+	reachedBeginning bool
+	// Whether the history around an older message was loaded instead of the latest one, so new messages are not appended until the gap is filled.
+	detached bool
+
+	// The last message that was read before the channel was opened, if it had unread messages then.
+	lastReadID discord.MessageID
+	// The message the "New messages" divider is drawn above.
+	firstUnreadID discord.MessageID
+
+	previews *imagePreviews
+	// Unlike the default renderer, it writes the links to messages as regions.
+	renderer renderer.Renderer
 }
 
 func ternary(cond bool, a, b string) string {
@@ -39,6 +71,8 @@ func newMessagesText(app *tview.Application, cfg *config.Config) *MessagesText {
 		TextView: tview.NewTextView(),
 		cfg:      cfg,
 		app:      app,
+		previews: newImagePreviews(app, cfg),
+		renderer: markdown.NewRenderer(),
 	}
 
 	mt.SetDynamicColors(true)
@@ -65,30 +99,401 @@ func newMessagesText(app *tview.Application, cfg *config.Config) *MessagesText {
 	mt.SetFocusBorderColor(tcell.GetColor(mt.cfg.Theme.FocusBorderColor))
 	mt.SetBorderPadding(p[0], p[1], p[2], p[3])
 
-	markdown.DefaultRenderer.AddOptions(
+	opts := []renderer.Option{
 		renderer.WithOption("emojiColor", mt.cfg.Theme.MessagesText.EmojiColor),
 		renderer.WithOption("linkColor", mt.cfg.Theme.MessagesText.LinkColor),
-	)
+		renderer.WithOption("userMention", markdown.MentionFunc[discord.GuildUser](mt.userMention)),
+		renderer.WithOption("roleMention", markdown.MentionFunc[discord.Role](mt.roleMention)),
+	}
+	markdown.DefaultRenderer.AddOptions(opts...)
+	mt.renderer.AddOptions(opts...)
+	mt.renderer.AddOptions(renderer.WithOption("linkTags", markdown.LinkTagsFunc(mt.linkTags)))
 
 	mt.SetHighlightedFunc(mt.onHighlighted)
+	mt.SetMouseCapture(mt.onMouseCapture)
 
 	return mt
 }
 
 func (mt *MessagesText) drawMsgs(cID discord.ChannelID) {
-	ms, err := discordState.Messages(cID, uint(mt.cfg.MessagesLimit))
+	limit := uint(mt.cfg.MessagesLimit)
+	ms, err := discordState.Messages(cID, limit)
 	if err != nil {
 		slog.Error("failed to get messages", "err", err, "channel_id", cID)
 		return
 	}
 
-	for _, m := range slices.Backward(ms) {
-		layout.messagesText.createMessage(m)
+	// The cabinet's messages share their reactions with ours, so copy them before they get updated.
+	for i := range ms {
+		ms[i].Reactions = slices.Clone(ms[i].Reactions)
+	}
+
+	mt.messages = ms
+	mt.reachedBeginning = len(ms) < int(limit)
+	mt.detached = false
+
+	mt.lastReadID = 0
+	if rs := discordState.ReadState.ReadState(cID); rs != nil && len(ms) > 0 && rs.LastMessageID < ms[0].ID {
+		mt.lastReadID = rs.LastMessageID
+	}
+
+	mt.render()
+}
+
+// Returns the oldest loaded message that was unread when the channel was opened. Nothing is returned while the first unread message might not be loaded yet.
+func (mt *MessagesText) findFirstUnread() discord.MessageID {
+	if !mt.lastReadID.IsValid() {
+		return 0
+	}
+
+	idx := -1
+	for i, m := range mt.messages {
+		if m.ID <= mt.lastReadID {
+			break
+		}
+
+		idx = i
+	}
+
+	if idx == -1 || (idx == len(mt.messages)-1 && !mt.reachedBeginning) {
+		return 0
+	}
+
+	return mt.messages[idx].ID
+}
+
+// Redraws the whole loaded history. Highlights are kept by the text view across clears.
+func (mt *MessagesText) render() {
+	w := mt.BatchWriter()
+	defer w.Close()
+
+	mt.firstUnreadID = mt.findFirstUnread()
+
+	w.Clear()
+	mt.createHistoryMarker(w)
+	for _, m := range slices.Backward(mt.messages) {
+		mt.createMessage(w, m)
+	}
+}
+
+func (mt *MessagesText) createHistoryMarker(w io.Writer) int {
+	switch {
+	case mt.fetchingOlder:
+		fmt.Fprintln(w, "[::d]Loading older messages…[::-]")
+	case mt.reachedBeginning:
+		fmt.Fprintln(w, "[::d]Beginning of channel[::-]")
+	default:
+		return 0
+	}
+
+	return 1
+}
+
+// Fetches the page of history before the oldest loaded message and prepends it to the view.
+func (mt *MessagesText) fetchOlder() {
+	cID := layout.guildsTree.selectedChannelID
+	if !cID.IsValid() || mt.fetchingOlder || mt.reachedBeginning || len(mt.messages) == 0 {
+		return
+	}
+
+	mt.fetchingOlder = true
+	mt.render()
+
+	oldest := mt.messages[len(mt.messages)-1]
+	limit := uint(mt.cfg.MessagesLimit)
+	go func() {
+		ms, err := discordState.MessagesBefore(cID, oldest.ID, limit)
+		mt.app.QueueUpdateDraw(func() {
+			// The channel may have been changed or reloaded in the meantime.
+			if layout.guildsTree.selectedChannelID != cID || len(mt.messages) == 0 || mt.messages[len(mt.messages)-1].ID != oldest.ID {
+				return
+			}
+
+			if err != nil {
+				slog.Error("failed to get older messages", "err", err, "channel_id", cID, "before", oldest.ID)
+				mt.fetchingOlder = false
+				mt.render()
+				return
+			}
+
+			// Messages fetched from the API do not have the guild ID filled.
+			for i := range ms {
+				ms[i].GuildID = oldest.GuildID
+			}
+
+			mt.prependMessages(ms, len(ms) < int(limit))
+		})
+	}()
+}
+
+// Fetches the page of history after the newest loaded message if it is not the latest of the channel.
+func (mt *MessagesText) fetchNewer() {
+	cID := layout.guildsTree.selectedChannelID
+	if !mt.detached || len(mt.messages) == 0 {
+		return
+	}
+
+	newest := mt.messages[0]
+	limit := uint(mt.cfg.MessagesLimit)
+	go func() {
+		ms, err := discordState.MessagesAfter(cID, newest.ID, limit)
+		mt.app.QueueUpdateDraw(func() {
+			if layout.guildsTree.selectedChannelID != cID || len(mt.messages) == 0 || mt.messages[0].ID != newest.ID {
+				return
+			}
+
+			if err != nil {
+				slog.Error("failed to get newer messages", "err", err, "channel_id", cID, "after", newest.ID)
+				return
+			}
+
+			for i := range ms {
+				ms[i].GuildID = newest.GuildID
+			}
+
+			mt.messages = append(ms, mt.messages...)
+			mt.detached = len(ms) >= int(limit)
+			mt.render()
+		})
+	}()
+}
+
+// Opens the channel with the message selected, loading the history around it if it is not loaded.
+func (mt *MessagesText) jumpToMessage(cID discord.ChannelID, mID discord.MessageID) {
+	gt := layout.guildsTree
+	if gt.selectedChannelID != cID {
+		gt.selectChannel(cID)
+		if gt.selectedChannelID != cID {
+			return
+		}
+	}
+
+	if !slices.ContainsFunc(mt.messages, func(m discord.Message) bool { return m.ID == mID }) {
+		limit := uint(mt.cfg.MessagesLimit)
+		ms, err := discordState.MessagesAround(cID, mID, limit)
+		if err != nil {
+			slog.Error("failed to get messages", "err", err, "channel_id", cID, "around", mID)
+			return
+		}
+
+		var gID discord.GuildID
+		if c, err := discordState.Cabinet.Channel(cID); err == nil {
+			gID = c.GuildID
+		}
+
+		for i := range ms {
+			ms[i].GuildID = gID
+		}
+
+		latest := len(mt.messages) > 0 && len(ms) > 0 && mt.messages[0].ID == ms[0].ID
+		mt.messages = ms
+		mt.reachedBeginning = false
+		mt.detached = !latest
+		mt.lastReadID = 0
+		mt.render()
+	}
+
+	mt.selectedMessageID = mID
+	mt.Highlight(mID.String())
+	mt.ScrollToHighlight()
+	mt.app.SetFocus(mt)
+}
+
+func (mt *MessagesText) prependMessages(ms []discord.Message, reachedBeginning bool) {
+	_, _, width, _ := mt.GetInnerRect()
+	before := wrappedLineCount(mt.GetText(false), width)
+	row, _ := mt.GetScrollOffset()
+
+	mt.messages = append(mt.messages, ms...)
+	mt.fetchingOlder = false
+	mt.reachedBeginning = reachedBeginning
+	mt.render()
+
+	// Keep the previously visible lines in place. Everything that was added (the older messages, the history marker and possibly the unread divider) is above them.
+	row += wrappedLineCount(mt.GetText(false), width) - before
+	mt.ScrollTo(max(row, 0), 0)
+}
+
+// Redraws the messages after the ignore rules change.
+func (mt *MessagesText) onIgnoresChange() {
+	if layout.guildsTree.selectedChannelID.IsValid() {
+		mt.render()
+	}
+}
+
+func (mt *MessagesText) onBlockedUsersChange() {
+	if mt.cfg.HideBlockedUsers && layout.guildsTree.selectedChannelID.IsValid() {
+		mt.render()
+	}
+}
+
+// Reports whether the latest message is visible as of the last draw.
+func (mt *MessagesText) isScrolledToEnd() bool {
+	_, _, width, height := mt.GetInnerRect()
+	row, _ := mt.GetScrollOffset()
+	return row+height >= wrappedLineCount(mt.GetText(false), width)
+}
+
+// Marks the selected channel as read up to the latest message unless automatic acks are disabled.
+func (mt *MessagesText) ackLatest() {
+	cID := layout.guildsTree.selectedChannelID
+	if !mt.cfg.AutoAck || !cID.IsValid() || len(mt.messages) == 0 || mt.detached {
+		return
+	}
+
+	discordState.ReadState.MarkRead(cID, mt.messages[0].ID)
+}
+
+// Returns the number of lines the text takes up in a text view of the given width.
+func wrappedLineCount(text string, width int) int {
+	text = strings.TrimSuffix(regionTagPattern.ReplaceAllString(text, ""), "\n")
+	if width <= 0 {
+		return strings.Count(text, "\n") + 1
+	}
+
+	var n int
+	for _, line := range strings.Split(text, "\n") {
+		n += max(len(tview.WordWrap(line, width)), 1)
+	}
+
+	return n
+}
+
+// Re-renders the region of an already drawn message in place.
+func (mt *MessagesText) redrawMessage(m discord.Message) {
+	text := mt.GetText(false)
+	start := strings.Index(text, fmt.Sprintf(`["%s"]`, m.ID))
+	if start == -1 {
+		return
+	}
+
+	end := strings.Index(text[start:], `[""]`)
+	if end == -1 {
+		return
+	}
+	end += start + len(`[""]`)
+
+	var b strings.Builder
+	mt.createMessage(&b, m)
+	mt.SetText(text[:start] + b.String() + text[end:])
+}
+
+// Redraws the loaded messages that show the image.
+func (mt *MessagesText) onPreviewLoaded(proxyURL string) {
+	for _, m := range mt.messages {
+		shows := slices.ContainsFunc(m.Attachments, func(a discord.Attachment) bool {
+			return string(a.Proxy) == proxyURL
+		}) || slices.ContainsFunc(m.Embeds, func(e discord.Embed) bool {
+			return (e.Image != nil && string(e.Image.Proxy) == proxyURL) || (e.Thumbnail != nil && string(e.Thumbnail.Proxy) == proxyURL)
+		})
+
+		if shows {
+			mt.redrawMessage(m)
+		}
+	}
+}
+
+func (mt *MessagesText) updateMessage(m discord.Message) {
+	mt.updateMessageFunc(m.ID, func(old *discord.Message) {
+		// Update events may only contain the changed fields.
+		defaultstore.DiffMessage(&m, old)
+		old.Reactions = slices.Clone(old.Reactions)
+		// Full updates, which carry the author, also carry whether the message is pinned.
+		if m.Author.ID.IsValid() {
+			old.Pinned = m.Pinned
+		}
+	})
+}
+
+// Marks the loaded messages of the channel as pinned if they are among the pins, and as unpinned otherwise.
+func (mt *MessagesText) syncPinned(cID discord.ChannelID, pins []discord.Message) {
+	for i, m := range mt.messages {
+		if m.ChannelID == cID {
+			mt.messages[i].Pinned = slices.ContainsFunc(pins, func(p discord.Message) bool {
+				return p.ID == m.ID
+			})
+		}
+	}
+}
+
+// Applies fn to the loaded message with the given ID and redraws it.
+func (mt *MessagesText) updateMessageFunc(mID discord.MessageID, fn func(m *discord.Message)) {
+	idx := slices.IndexFunc(mt.messages, func(m discord.Message) bool {
+		return m.ID == mID
+	})
+	if idx == -1 {
+		return
 	}
+
+	fn(&mt.messages[idx])
+	mt.redrawMessage(mt.messages[idx])
+}
+
+func (mt *MessagesText) addReaction(mID discord.MessageID, e discord.Emoji, me bool) {
+	mt.updateMessageFunc(mID, func(m *discord.Message) {
+		idx := slices.IndexFunc(m.Reactions, func(r discord.Reaction) bool {
+			return emojiEqual(r.Emoji, e)
+		})
+		if idx == -1 {
+			m.Reactions = append(m.Reactions, discord.Reaction{Emoji: e})
+			idx = len(m.Reactions) - 1
+		}
+
+		m.Reactions[idx].Count++
+		m.Reactions[idx].Me = m.Reactions[idx].Me || me
+	})
+}
+
+func (mt *MessagesText) removeReaction(mID discord.MessageID, e discord.Emoji, me bool) {
+	mt.updateMessageFunc(mID, func(m *discord.Message) {
+		idx := slices.IndexFunc(m.Reactions, func(r discord.Reaction) bool {
+			return emojiEqual(r.Emoji, e)
+		})
+		if idx == -1 {
+			return
+		}
+
+		if m.Reactions[idx].Count <= 1 {
+			m.Reactions = slices.Delete(m.Reactions, idx, idx+1)
+			return
+		}
+
+		m.Reactions[idx].Count--
+		if me {
+			m.Reactions[idx].Me = false
+		}
+	})
+}
+
+// Removes the reactions of the given emoji, or every reaction if the emoji is nil.
+func (mt *MessagesText) clearReactions(mID discord.MessageID, e *discord.Emoji) {
+	mt.updateMessageFunc(mID, func(m *discord.Message) {
+		if e == nil {
+			m.Reactions = nil
+			return
+		}
+
+		m.Reactions = slices.DeleteFunc(m.Reactions, func(r discord.Reaction) bool {
+			return emojiEqual(r.Emoji, *e)
+		})
+	})
+}
+
+func (mt *MessagesText) removeMessage(mID discord.MessageID) {
+	mt.messages = slices.DeleteFunc(mt.messages, func(m discord.Message) bool {
+		return m.ID == mID
+	})
 }
 
 func (mt *MessagesText) reset() {
-	layout.messagesText.selectedMessageID = 0
+	mt.selectedMessageID = 0
+	mt.messages = nil
+	mt.fetchingOlder = false
+	mt.reachedBeginning = false
+	mt.detached = false
+	mt.lastReadID = 0
+	mt.firstUnreadID = 0
+	mt.previews.reset()
 
 	mt.SetTitle("")
 	mt.SetTitlePadding(1, 1)
@@ -98,94 +503,291 @@ func (mt *MessagesText) reset() {
 
 // Region tags are square brackets that contain a region ID in double quotes
 // https://pkg.go.dev/github.com/0xJWLabs/tview#hdr-Regions_and_Highlights
-func (mt *MessagesText) startRegion(msgID discord.MessageID) {
-	fmt.Fprintf(mt, `["%s"]`, msgID)
+func (mt *MessagesText) startRegion(w io.Writer, msgID discord.MessageID) {
+	fmt.Fprintf(w, `["%s"]`, msgID)
 }
 
 // Tags with no region ID ([""]) don't start new regions. They can therefore be used to mark the end of a region.
-func (mt *MessagesText) endRegion() {
-	fmt.Fprint(mt, `[""]`)
+func (mt *MessagesText) endRegion(w io.Writer) {
+	fmt.Fprint(w, `[""]`)
 }
 
-func (mt *MessagesText) createMessage(m discord.Message) {
-	mt.startRegion(m.ID)
-	defer mt.endRegion()
+func (mt *MessagesText) createMessage(w io.Writer, m discord.Message) {
+	mt.startRegion(w, m.ID)
+	defer mt.endRegion(w)
+
+	// The divider is part of the region so that redrawing the message in place does not write it again.
+	if m.ID == mt.firstUnreadID {
+		fmt.Fprintf(w, "[%s]──── New messages ────[-]\n", mt.cfg.Theme.MessagesText.NewMessagesColor)
+	}
 
 	if mt.cfg.HideBlockedUsers {
 		isBlocked := discordState.UserIsBlocked(m.Author.ID)
 		if isBlocked {
-			fmt.Fprintln(mt, "[:red:b]Blocked message[:-:-]")
+			fmt.Fprintln(w, "[:red:b]Blocked message[:-:-]")
 			return
 		}
 	}
 
+	action := layout.ignores.action(m)
+	switch {
+	case action == ignore.Hide:
+		return
+	case layout.ignores.collapsed(m):
+		name := userName(mt.cfg, m.Author, guildMember(m.GuildID, m.Author.ID, nil))
+		fmt.Fprintf(w, "[::d]Ignored message from %s (%s to expand)[::-]\n", tview.Escape(name), mt.cfg.Keys.MessagesText.ToggleCollapsed)
+		return
+	}
+
 	switch m.Type {
 	case discord.ChannelPinnedMessage:
-		fmt.Fprint(mt, "["+mt.cfg.Theme.MessagesText.ContentColor+"]"+m.Author.Username+" pinned a message"+"[-:-:-]")
+		name := userName(mt.cfg, m.Author, guildMember(m.GuildID, m.Author.ID, nil))
+		fmt.Fprint(w, "["+mt.cfg.Theme.MessagesText.ContentColor+"]"+tview.Escape(name)+" pinned a message"+"[-:-:-]")
 	case discord.DefaultMessage, discord.InlinedReplyMessage:
 		if m.ReferencedMessage != nil {
-			mt.createHeader(mt, *m.ReferencedMessage, true)
-			mt.createBody(mt, *m.ReferencedMessage, true)
+			mt.createHeader(w, *m.ReferencedMessage, true)
+			mt.createBody(w, *m.ReferencedMessage, m.ID, true)
+
+			fmt.Fprint(w, "[::-]\n")
+		}
 
-			fmt.Fprint(mt, "[::-]\n")
+		if layout.highlights.matches(m) {
+			fmt.Fprintf(w, "[%s]▌[-]", mt.cfg.Theme.MessagesText.HighlightColor)
 		}
 
-		mt.createHeader(mt, m, false)
-		mt.createBody(mt, m, false)
-		mt.createFooter(mt, m)
+		if action == ignore.Dim {
+			// The styles within the message reset its attributes, so it is dimmed again after each of them.
+			var b strings.Builder
+			mt.createContent(&b, m)
+			fmt.Fprint(w, "[::d]"+attributesResetPattern.ReplaceAllString(b.String(), "${0}[::d]")+"[::-]")
+		} else {
+			mt.createContent(w, m)
+		}
 	default:
-		mt.createHeader(mt, m, false)
+		mt.createHeader(w, m, false)
+	}
+
+	fmt.Fprintln(w)
+}
+
+func (mt *MessagesText) createContent(w io.Writer, m discord.Message) {
+	mt.createHeader(w, m, false)
+	mt.createBody(w, m, m.ID, false)
+	mt.createEmbeds(w, m)
+	mt.createFooter(w, m)
+	mt.createThreadLine(w, m)
+}
+
+func formatTimestamp(cfg *config.Config, t time.Time) string {
+	// Get the local time from the timestamp
+	t = t.In(time.Local)
+
+	// Check if the timestamp is from today
+	if cfg.TimestampsFormat == "relative" {
+		now := time.Now()
+		if t.Year() == now.Year() && t.YearDay() == now.YearDay() {
+			return fmt.Sprintf("Today at %s", t.Format(time.Kitchen))
+		}
+
+		return t.Format("January 2, 2006 3:04 PM")
 	}
 
-	fmt.Fprintln(mt)
+	return t.Format(cfg.TimestampsFormat)
 }
 
 func (mt *MessagesText) createHeader(w io.Writer, m discord.Message, isReply bool) {
 	clientID := discordState.Ready().User.ID
 
 	if mt.cfg.Timestamps {
-		// Get the local time from the message's timestamp
-		msgTime := m.Timestamp.Time().In(time.Local)
-
-		// Format the timestamp based on whether it's today or not
-		var timeString string
-		
-		// Check if the timestamp is from today
-		if mt.cfg.TimestampsFormat == "relative" {
-			now := time.Now()
-			if msgTime.Year() == now.Year() && msgTime.YearDay() == now.YearDay() {
-				timeString = fmt.Sprintf("Today at %s", msgTime.Format(time.Kitchen))
-			} else {
-				timeString = msgTime.Format("January 2, 2006 3:04 PM")
-			}
-		} else {
-			timeString = msgTime.Format(mt.cfg.TimestampsFormat)
-		}
-
 		// Print the formatted time
-		fmt.Fprintf(w, "[::d]%s[::-] ", timeString)
+		fmt.Fprintf(w, "[::d]%s[::-] ", formatTimestamp(mt.cfg, m.Timestamp.Time()))
 	}
 
 	if isReply {
-		fmt.Fprintf(mt, "[::d]%s", mt.cfg.Theme.MessagesText.ReplyIndicator)
+		fmt.Fprintf(w, "[::d]%s", mt.cfg.Theme.MessagesText.ReplyIndicator)
 	}
 
+	member := guildMember(m.GuildID, m.Author.ID, nil)
 	color := ternary(m.Author.ID != clientID, mt.cfg.Theme.MessagesText.AuthorColor, mt.cfg.Theme.MessagesText.UserColor)
+	color = userColor(mt.cfg, m.GuildID, member, color)
+
+	fmt.Fprintf(w, "[%s]%s[-:-:-] ", color, tview.Escape(userName(mt.cfg, m.Author, member)))
+}
+
+func (mt *MessagesText) userMention(gID discord.GuildID, u discord.GuildUser) (string, string) {
+	m := guildMember(gID, u.ID, u.Member)
+	return userName(mt.cfg, u.User, m), userColor(mt.cfg, gID, m, "-")
+}
+
+func (mt *MessagesText) roleMention(gID discord.GuildID, r discord.Role) (string, string) {
+	// The role of the mention may only have its ID.
+	if cached, err := discordState.Cabinet.Role(gID, r.ID); err == nil {
+		r = *cached
+	}
+
+	if mt.cfg.RoleColors && r.Color > 0 {
+		return r.Name, r.Color.String()
+	}
 
-	fmt.Fprintf(w, "[%s]%s[-:-:-] ", color, m.Author.Username)
+	return r.Name, "-"
 }
 
-func (mt *MessagesText) createBody(w io.Writer, m discord.Message, isReply bool) {
+// The region of the message with regionID is started again after the links to messages in the body.
+func (mt *MessagesText) createBody(w io.Writer, m discord.Message, regionID discord.MessageID, isReply bool) {
 	if isReply {
 		fmt.Fprint(w, "[::d]")
 	}
 
-	src := []byte(m.Content)
-	ast := discordmd.ParseWithMessage(src, *discordState.Cabinet, &m, false)
-	markdown.DefaultRenderer.Render(w, src, ast)
+	mt.renderMarkdown(w, m.Content, m, regionID)
 
 	if isReply {
 		fmt.Fprint(w, "[::-]")
+	} else if m.EditedTimestamp.IsValid() {
+		fmt.Fprint(w, " [::d](edited)[::-]")
+	}
+}
+
+func (mt *MessagesText) createEmbeds(w io.Writer, m discord.Message) {
+	// Leave room for the border.
+	_, _, width, _ := mt.GetInnerRect()
+	width -= 2
+
+	for _, e := range m.Embeds {
+		var b strings.Builder
+		mt.createEmbed(&b, m, e, width)
+		if b.Len() == 0 {
+			continue
+		}
+
+		borderColor := mt.cfg.Theme.MessagesText.EmbedBorderColor
+		if e.Color > 0 {
+			borderColor = e.Color.String()
+		}
+
+		// The border resets the color, so the color that was set on the previous line is set again after it.
+		color := "-"
+		for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n") {
+			fmt.Fprintf(w, "\n[%s]%c[%s] %s", borderColor, tview.BoxDrawingsHeavyVertical, color, line)
+			if tags := foregroundTagPattern.FindAllStringSubmatch(line, -1); len(tags) > 0 {
+				color = tags[len(tags)-1][1]
+			}
+		}
+	}
+}
+
+func (mt *MessagesText) createEmbed(w io.Writer, m discord.Message, e discord.Embed, width int) {
+	theme := mt.cfg.Theme.MessagesText
+
+	if e.Provider != nil && e.Provider.Name != "" {
+		fmt.Fprintf(w, "[%s]%s[-]\n", theme.EmbedFooterColor, tview.Escape(e.Provider.Name))
+	}
+
+	if e.Author != nil && e.Author.Name != "" {
+		fmt.Fprintf(w, "[%s::b]%s[-::-]\n", theme.EmbedAuthorColor, tview.Escape(e.Author.Name))
+	}
+
+	if e.Title != "" {
+		fmt.Fprintf(w, "[%s::b:%s]%s[-::-:-]\n", theme.EmbedTitleColor, e.URL, tview.Escape(e.Title))
+	}
+
+	if e.Description != "" {
+		fmt.Fprintf(w, "[%s]", theme.EmbedDescriptionColor)
+		mt.renderMarkdown(w, e.Description, m, m.ID)
+		fmt.Fprint(w, "[-]\n")
+	}
+
+	mt.createEmbedFields(w, e.Fields, width)
+
+	switch {
+	case e.Image != nil:
+		mt.previews.write(w, string(e.Image.Proxy), e.Image.Width, e.Image.Height, width)
+		fmt.Fprintln(w)
+	case e.Thumbnail != nil:
+		mt.previews.write(w, string(e.Thumbnail.Proxy), e.Thumbnail.Width, e.Thumbnail.Height, width)
+		fmt.Fprintln(w)
+	}
+
+	var footer []string
+	if e.Footer != nil && e.Footer.Text != "" {
+		footer = append(footer, tview.Escape(e.Footer.Text))
+	}
+
+	if e.Timestamp.IsValid() {
+		footer = append(footer, formatTimestamp(mt.cfg, e.Timestamp.Time()))
+	}
+
+	if len(footer) > 0 {
+		fmt.Fprintf(w, "[%s]%s[-]\n", theme.EmbedFooterColor, strings.Join(footer, " • "))
+	}
+}
+
+// Lays out consecutive inline fields in rows of up to three columns and every other field on its own row.
+func (mt *MessagesText) createEmbedFields(w io.Writer, fs []discord.EmbedField, width int) {
+	for i := 0; i < len(fs); {
+		n := 1
+		// The width is unknown until the text view is drawn for the first time.
+		if fs[i].Inline && width > 0 {
+			for n < 3 && i+n < len(fs) && fs[i+n].Inline {
+				n++
+			}
+		}
+
+		mt.createEmbedFieldRow(w, fs[i:i+n], width)
+		i += n
+	}
+}
+
+func (mt *MessagesText) createEmbedFieldRow(w io.Writer, fs []discord.EmbedField, width int) {
+	theme := mt.cfg.Theme.MessagesText
+
+	wrap := func(text string, width int) []string {
+		if width <= 0 {
+			return strings.Split(text, "\n")
+		}
+
+		var lines []string
+		for _, line := range strings.Split(text, "\n") {
+			lines = append(lines, tview.WordWrap(line, width)...)
+		}
+
+		return lines
+	}
+
+	// Columns are separated by a single space.
+	colWidth := max(width/len(fs)-1, 1)
+	if width <= 0 {
+		colWidth = 0
+	}
+
+	columns := make([][]string, len(fs))
+	var height int
+	for i, f := range fs {
+		for _, line := range wrap(tview.Escape(f.Name), colWidth) {
+			columns[i] = append(columns[i], fmt.Sprintf("[%s::b]%s[-::-]", theme.EmbedFieldNameColor, line))
+		}
+
+		for _, line := range wrap(tview.Escape(f.Value), colWidth) {
+			columns[i] = append(columns[i], fmt.Sprintf("[%s]%s[-]", theme.EmbedFieldValueColor, line))
+		}
+
+		height = max(height, len(columns[i]))
+	}
+
+	for row := range height {
+		for i, col := range columns {
+			var cell string
+			if row < len(col) {
+				cell = col[row]
+			}
+
+			if i < len(columns)-1 {
+				cell += strings.Repeat(" ", max(colWidth+1-tview.TaggedStringWidth(cell), 0))
+			}
+
+			io.WriteString(w, cell)
+		}
+
+		fmt.Fprintln(w)
 	}
 }
 
@@ -197,40 +799,66 @@ func (mt *MessagesText) createFooter(w io.Writer, m discord.Message) {
 		} else {
 			fmt.Fprintf(w, "[%s][%s][-]", mt.cfg.Theme.MessagesText.AttachmentColor, a.Filename)
 		}
+
+		if strings.HasPrefix(a.ContentType, "image/") {
+			_, _, width, _ := mt.GetInnerRect()
+			mt.previews.write(w, string(a.Proxy), a.Width, a.Height, width)
+		}
+	}
+
+	if len(m.Reactions) > 0 {
+		fmt.Fprintln(w)
+		for _, r := range m.Reactions {
+			color := ternary(r.Me, mt.cfg.Theme.MessagesText.ReactionSelfColor, mt.cfg.Theme.MessagesText.ReactionColor)
+			fmt.Fprintf(w, "[%s]%s %d[-] ", color, emojiToString(r.Emoji), r.Count)
+		}
 	}
 }
 
-func (mt *MessagesText) getSelectedMessage() (*discord.Message, error) {
-	if !mt.selectedMessageID.IsValid() {
-		return nil, errors.New("no message is currently selected")
+func (mt *MessagesText) createThreadLine(w io.Writer, m discord.Message) {
+	if m.Flags&discord.MessageHasThread == 0 {
+		return
 	}
 
-	msg, err := discordState.Cabinet.Message(layout.guildsTree.selectedChannelID, mt.selectedMessageID)
+	// Threads started from a message share its ID.
+	fmt.Fprintf(w, "\n[\"%s%s\"][%s]", threadRegionPrefix, m.ID, mt.cfg.Theme.MessagesText.ThreadColor)
+	defer fmt.Fprint(w, "[-]")
+
+	t, err := discordState.Cabinet.Channel(discord.ChannelID(m.ID))
 	if err != nil {
-		return nil, fmt.Errorf("could not retrieve selected message: %w", err)
+		fmt.Fprint(w, "Thread")
+		return
 	}
 
-	return msg, nil
+	fmt.Fprintf(w, "%s: %d %s", tview.Escape(t.Name), t.MessageCount, ternary(t.MessageCount == 1, "reply", "replies"))
+	if t.LastMessageID.IsValid() {
+		fmt.Fprintf(w, ", last activity %s", formatTimestamp(mt.cfg, t.LastMessageID.Time()))
+	}
 }
 
-func (mt *MessagesText) getSelectedMessageIndex() (int, error) {
-	ms, err := discordState.Cabinet.Messages(layout.guildsTree.selectedChannelID)
-	if err != nil {
-		return -1, err
+func (mt *MessagesText) getSelectedMessage() (*discord.Message, error) {
+	if !mt.selectedMessageID.IsValid() {
+		return nil, errors.New("no message is currently selected")
 	}
 
-	for idx, m := range ms {
-		for m.ID == mt.selectedMessageID {
-			return idx, nil
-		}
+	idx := mt.getSelectedMessageIndex()
+	if idx == -1 {
+		return nil, fmt.Errorf("could not retrieve selected message %s", mt.selectedMessageID)
 	}
 
-	return -1, nil
+	msg := mt.messages[idx]
+	return &msg, nil
+}
+
+func (mt *MessagesText) getSelectedMessageIndex() int {
+	return slices.IndexFunc(mt.messages, func(m discord.Message) bool {
+		return m.ID == mt.selectedMessageID
+	})
 }
 
 func (mt *MessagesText) onInputCapture(event *tcell.EventKey) *tcell.EventKey {
 	switch event.Name() {
-	case mt.cfg.Keys.SelectPrevious, mt.cfg.Keys.SelectNext, mt.cfg.Keys.SelectFirst, mt.cfg.Keys.SelectLast, mt.cfg.Keys.MessagesText.SelectReply, mt.cfg.Keys.MessagesText.SelectPin:
+	case mt.cfg.Keys.SelectPrevious, mt.cfg.Keys.SelectNext, mt.cfg.Keys.SelectFirst, mt.cfg.Keys.SelectLast, mt.cfg.Keys.MessagesText.SelectReply, mt.cfg.Keys.MessagesText.SelectPin, mt.cfg.Keys.MessagesText.SelectUnread:
 		mt._select(event.Name())
 		return nil
 	case mt.cfg.Keys.MessagesText.Yank:
@@ -239,62 +867,128 @@ func (mt *MessagesText) onInputCapture(event *tcell.EventKey) *tcell.EventKey {
 	case mt.cfg.Keys.MessagesText.Open:
 		mt.open()
 		return nil
+	case mt.cfg.Keys.MessagesText.OpenLink:
+		mt.openLink()
+		return nil
+	case mt.cfg.Keys.MessagesText.Download:
+		mt.download()
+		return nil
+	case mt.cfg.Keys.MessagesText.CancelDownloads:
+		layout.downloads.cancelAll()
+		return nil
 	case mt.cfg.Keys.MessagesText.Reply:
 		mt.reply(false)
 		return nil
 	case mt.cfg.Keys.MessagesText.ReplyMention:
 		mt.reply(true)
 		return nil
+	case mt.cfg.Keys.MessagesText.React:
+		mt.react()
+		return nil
+	case mt.cfg.Keys.MessagesText.Edit:
+		mt.edit()
+		return nil
+	case mt.cfg.Keys.MessagesText.OpenThread:
+		mt.openThread()
+		return nil
+	case mt.cfg.Keys.MessagesText.CreateThread:
+		mt.createThread()
+		return nil
 	case mt.cfg.Keys.MessagesText.Delete:
 		mt.delete()
 		return nil
+	case mt.cfg.Keys.MessagesText.ShowProfile:
+		mt.showProfile()
+		return nil
+	case mt.cfg.Keys.MessagesText.Ignore:
+		if msg, err := mt.getSelectedMessage(); err == nil {
+			showIgnoreForm(mt.cfg, msg.GuildID, msg.ChannelID, msg.Author)
+		}
+		return nil
+	case mt.cfg.Keys.MessagesText.ToggleCollapsed:
+		if msg, err := mt.getSelectedMessage(); err == nil && layout.ignores.action(*msg) == ignore.Collapse {
+			layout.ignores.toggleExpanded(msg.ID)
+			mt.render()
+		}
+		return nil
+	case mt.cfg.Keys.MessagesText.ShowPins:
+		layout.pinnedMessages.show(layout.guildsTree.selectedChannelID)
+		return nil
+	case mt.cfg.Keys.MessagesText.TogglePin:
+		if msg, err := mt.getSelectedMessage(); err == nil {
+			togglePin(*msg)
+		}
+		return nil
 	}
 
 	return nil
 }
 
 func (mt *MessagesText) _select(name string) {
-	ms, err := discordState.Cabinet.Messages(layout.guildsTree.selectedChannelID)
-	if err != nil {
-		slog.Error("failed to get messages", "err", err, "channel_id", layout.guildsTree.selectedChannelID)
+	ms := mt.messages
+	if len(ms) == 0 {
 		return
 	}
 
-	messageIdx, err := mt.getSelectedMessageIndex()
-	if err != nil {
-		slog.Error("failed to get selected message", "err", err)
-		return
-	}
+	messageIdx := mt.getSelectedMessageIndex()
 
 	switch name {
 	case mt.cfg.Keys.SelectPrevious:
 		// If no message is currently selected, select the latest message.
 		if len(mt.GetHighlights()) == 0 {
-			mt.selectedMessageID = ms[0].ID
-		} else {
-			if messageIdx < len(ms)-1 {
-				mt.selectedMessageID = ms[messageIdx+1].ID
-			} else {
-				return
-			}
+			messageIdx = -1
+		}
+
+		idx := mt.visibleIndex(messageIdx+1, 1)
+		if idx == -1 {
+			// The oldest loaded message is selected; load the page before it.
+			mt.fetchOlder()
+			return
 		}
+
+		mt.selectedMessageID = ms[idx].ID
 	case mt.cfg.Keys.SelectNext:
 		// If no message is currently selected, select the latest message.
 		if len(mt.GetHighlights()) == 0 {
-			mt.selectedMessageID = ms[0].ID
-		} else {
-			if messageIdx > 0 {
-				mt.selectedMessageID = ms[messageIdx-1].ID
-			} else {
+			idx := mt.visibleIndex(0, 1)
+			if idx == -1 {
 				return
 			}
+
+			mt.selectedMessageID = ms[idx].ID
+			break
+		}
+
+		idx := mt.visibleIndex(messageIdx-1, -1)
+		if idx == -1 {
+			// The newest loaded message is selected; load the page after it.
+			mt.fetchNewer()
+			return
 		}
+
+		mt.selectedMessageID = ms[idx].ID
 	case mt.cfg.Keys.SelectFirst:
-		mt.selectedMessageID = ms[len(ms)-1].ID
+		idx := mt.visibleIndex(len(ms)-1, -1)
+		if idx == -1 {
+			return
+		}
+
+		mt.selectedMessageID = ms[idx].ID
+		defer mt.fetchOlder()
 	case mt.cfg.Keys.SelectLast:
-		mt.selectedMessageID = ms[0].ID
+		if mt.detached {
+			mt.drawMsgs(layout.guildsTree.selectedChannelID)
+			ms = mt.messages
+		}
+
+		idx := mt.visibleIndex(0, 1)
+		if idx == -1 {
+			return
+		}
+
+		mt.selectedMessageID = ms[idx].ID
 	case mt.cfg.Keys.MessagesText.SelectReply:
-		if mt.selectedMessageID == 0 {
+		if messageIdx == -1 {
 			return
 		}
 
@@ -306,6 +1000,10 @@ func (mt *MessagesText) _select(name string) {
 			}
 		}
 	case mt.cfg.Keys.MessagesText.SelectPin:
+		if messageIdx == -1 {
+			return
+		}
+
 		if ref := ms[messageIdx].Reference; ref != nil {
 			for _, m := range ms {
 				if ref.MessageID == m.ID {
@@ -313,14 +1011,72 @@ func (mt *MessagesText) _select(name string) {
 				}
 			}
 		}
+	case mt.cfg.Keys.MessagesText.SelectUnread:
+		if !mt.firstUnreadID.IsValid() {
+			// The first unread message is older than the loaded history.
+			if mt.lastReadID.IsValid() {
+				mt.fetchOlder()
+			}
+
+			return
+		}
+
+		mt.selectedMessageID = mt.firstUnreadID
 	}
 
 	mt.Highlight(mt.selectedMessageID.String())
 	mt.ScrollToHighlight()
+
+	if mt.selectedMessageID == ms[0].ID {
+		mt.ackLatest()
+	}
+}
+
+// Returns the index of the first loaded message from idx on in the direction of step that is not hidden by an ignore rule, or -1 if there is none.
+func (mt *MessagesText) visibleIndex(idx, step int) int {
+	for ; idx >= 0 && idx < len(mt.messages); idx += step {
+		if layout.ignores.action(mt.messages[idx]) != ignore.Hide {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+func (mt *MessagesText) onMouseCapture(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+	// Scrolling up while already at the top loads older history.
+	if action == tview.MouseScrollUp {
+		if row, _ := mt.GetScrollOffset(); row <= 0 {
+			mt.fetchOlder()
+		}
+	}
+
+	return action, event
 }
 
 func (mt *MessagesText) onHighlighted(added, removed, remaining []string) {
 	if len(added) > 0 {
+		if id, ok := strings.CutPrefix(added[0], threadRegionPrefix); ok {
+			tID, err := discord.ParseSnowflake(id)
+			if err != nil {
+				slog.Error("failed to parse thread region id", "err", err, "region", added[0])
+				return
+			}
+
+			// The thread is opened from a queued update since it resets this text view.
+			go mt.app.QueueUpdateDraw(func() {
+				layout.guildsTree.selectChannel(discord.ChannelID(tID))
+			})
+			return
+		}
+
+		if link, ok := parseMessageLinkRegion(added[0]); ok {
+			go mt.app.QueueUpdateDraw(func() {
+				mt.jumpToLink(link)
+			})
+			return
+		}
+
 		mID, err := strconv.ParseInt(added[0], 10, 64)
 		if err != nil {
 			slog.Error("Failed to parse region id as int to use as message id.", "err", err)
@@ -330,6 +1086,16 @@ func (mt *MessagesText) onHighlighted(added, removed, remaining []string) {
 	}
 }
 
+func (mt *MessagesText) showProfile() {
+	msg, err := mt.getSelectedMessage()
+	if err != nil {
+		slog.Error("failed to get selected message", "err", err)
+		return
+	}
+
+	showUserProfile(mt.cfg, msg.GuildID, msg.Author)
+}
+
 func (mt *MessagesText) yank() {
 	msg, err := mt.getSelectedMessage()
 	if err != nil {
@@ -365,6 +1131,36 @@ func (mt *MessagesText) open() {
 	}
 }
 
+// Jumps to the first link to a message or channel in the selected message instead of opening it in the browser.
+func (mt *MessagesText) openLink() {
+	msg, err := mt.getSelectedMessage()
+	if err != nil {
+		slog.Error("failed to get selected message", "err", err)
+		return
+	}
+
+	for _, word := range strings.Fields(msg.Content) {
+		word = strings.Trim(word, "<>()")
+		if messageLinkPattern.MatchString(word) {
+			link, _ := parseMessageLink(word)
+			mt.jumpToLink(link)
+			return
+		}
+	}
+}
+
+func (mt *MessagesText) download() {
+	msg, err := mt.getSelectedMessage()
+	if err != nil {
+		slog.Error("failed to get selected message", "err", err)
+		return
+	}
+
+	for _, a := range msg.Attachments {
+		layout.downloads.start(a)
+	}
+}
+
 func (mt *MessagesText) reply(mention bool) {
 	var title string
 	if mention {
@@ -380,12 +1176,136 @@ func (mt *MessagesText) reply(mention bool) {
 	}
 
 	title += msg.Author.Tag()
-	layout.messageInput.SetTitle(title)
-	layout.messageInput.SetTitlePadding(1, 1)
+	layout.messageInput.setTitle(title)
 	layout.messageInput.replyMessageID = mt.selectedMessageID
 	mt.app.SetFocus(layout.messageInput)
 }
 
+func (mt *MessagesText) react() {
+	msg, err := mt.getSelectedMessage()
+	if err != nil {
+		slog.Error("failed to get selected message", "err", err)
+		return
+	}
+
+	cID, mID := layout.guildsTree.selectedChannelID, msg.ID
+	picker := newEmojiPicker(mt.cfg, msg.GuildID, func(e discord.Emoji) {
+		// Reacting with an emoji we already reacted with removes our reaction.
+		var me bool
+		for _, r := range msg.Reactions {
+			if emojiEqual(r.Emoji, e) {
+				me = r.Me
+				break
+			}
+		}
+
+		go func() {
+			var err error
+			if me {
+				err = discordState.Unreact(cID, mID, e.APIString())
+			} else {
+				err = discordState.React(cID, mID, e.APIString())
+			}
+
+			if err != nil {
+				slog.Error("failed to toggle reaction", "err", err, "channel_id", cID, "message_id", mID, "emoji", e.Name)
+			}
+		}()
+	})
+
+	layout.showModal(emojiPickerPageName, picker, 50, 20)
+}
+
+func (mt *MessagesText) openThread() {
+	msg, err := mt.getSelectedMessage()
+	if err != nil {
+		slog.Error("failed to get selected message", "err", err)
+		return
+	}
+
+	switch {
+	case msg.Flags&discord.MessageHasThread != 0:
+		layout.guildsTree.selectChannel(discord.ChannelID(msg.ID))
+	case msg.Type == discord.ThreadCreatedMessage && msg.Reference != nil:
+		layout.guildsTree.selectChannel(msg.Reference.ChannelID)
+	}
+}
+
+func (mt *MessagesText) createThread() {
+	msg, err := mt.getSelectedMessage()
+	if err != nil {
+		slog.Error("failed to get selected message", "err", err)
+		return
+	}
+
+	cID := layout.guildsTree.selectedChannelID
+	c, err := discordState.Cabinet.Channel(cID)
+	if err != nil {
+		slog.Error("failed to get channel", "err", err, "channel_id", cID)
+		return
+	}
+
+	if c.Type != discord.GuildText && c.Type != discord.GuildAnnouncement {
+		return
+	}
+
+	if msg.Flags&discord.MessageHasThread != 0 {
+		layout.guildsTree.selectChannel(discord.ChannelID(msg.ID))
+		return
+	}
+
+	// Default to the beginning of the first line of the message like the official client does.
+	name, _, _ := strings.Cut(msg.Content, "\n")
+	if r := []rune(name); len(r) > 40 {
+		name = string(r[:40])
+	}
+
+	showPrompt(mt.cfg, "Thread name", name, func(name string) {
+		data := api.StartThreadData{
+			Name:                name,
+			AutoArchiveDuration: c.DefaultAutoArchiveDuration,
+		}
+		if data.AutoArchiveDuration == 0 {
+			data.AutoArchiveDuration = discord.OneDayArchive
+		}
+
+		go func() {
+			t, err := discordState.StartThreadWithMessage(cID, msg.ID, data)
+			if err != nil {
+				slog.Error("failed to create thread", "err", err, "channel_id", cID, "message_id", msg.ID)
+				return
+			}
+
+			if err := discordState.Cabinet.ChannelSet(t, false); err != nil {
+				slog.Error("failed to set thread", "err", err, "thread_id", t.ID)
+			}
+
+			mt.app.QueueUpdateDraw(func() {
+				layout.guildsTree.onThreadCreate(*t)
+				layout.guildsTree.selectChannel(t.ID)
+			})
+		}()
+	})
+}
+
+func (mt *MessagesText) edit() {
+	msg, err := mt.getSelectedMessage()
+	if err != nil {
+		slog.Error("failed to get selected message", "err", err)
+		return
+	}
+
+	if msg.Author.ID != discordState.Ready().User.ID {
+		return
+	}
+
+	layout.messageInput.reset()
+	layout.messageInput.setTitle("Editing message")
+	layout.messageInput.setText(msg.Content)
+	layout.messageInput.editMessageID = msg.ID
+	mt.app.SetFocus(layout.messageInput)
+}
+
 func (mt *MessagesText) delete() {
 	msg, err := mt.getSelectedMessage()
 	if err != nil {
@@ -419,15 +1339,6 @@ func (mt *MessagesText) delete() {
 		return
 	}
 
-	ms, err := discordState.Cabinet.Messages(layout.guildsTree.selectedChannelID)
-	if err != nil {
-		slog.Error("failed to delete message", "err", err, "channel_id", layout.guildsTree.selectedChannelID)
-		return
-	}
-
-	mt.Clear()
-
-	for _, m := range slices.Backward(ms) {
-		layout.messagesText.createMessage(m)
-	}
+	mt.removeMessage(msg.ID)
+	mt.render()
 }
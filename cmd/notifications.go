@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/0xJWLabs/discordo/internal/ignore"
+	"github.com/0xJWLabs/discordo/internal/notify"
+	"github.com/0xJWLabs/tview"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/ningen/v3"
+	"github.com/gdamore/tcell/v2"
+)
+
+// The maximum number of characters of the content of a message shown in its notification.
+const notificationBodyLength = 200
+
+// Shows desktop notifications for the messages that would notify in the official clients, or as configured.
+type notifications struct {
+	cfg *config.Config
+	app *tview.Application
+
+	// Set once D-Bus fails with the "auto" method, after which only the escape sequences are used.
+	dbusFailed bool
+	// The escape sequences that are written to the terminal after the next draw, so that they do not end up in the middle of the output of the screen.
+	pending []string
+}
+
+func newNotifications(app *tview.Application, cfg *config.Config) *notifications {
+	return &notifications{cfg: cfg, app: app}
+}
+
+func (n *notifications) onMessageCreate(m discord.Message) {
+	if !n.cfg.Notifications.Enabled || !n.shouldNotify(m) {
+		return
+	}
+
+	title, body := n.format(m)
+	method := notify.Method(n.cfg.Notifications.Method)
+	if method != notify.DBus && (method != notify.Auto || n.dbusFailed) {
+		n.pending = append(n.pending, notify.Escape(method, title, body))
+		return
+	}
+
+	go func() {
+		err := notify.SendDBus(config.Name, title, body)
+		if err == nil {
+			return
+		}
+
+		if method == notify.DBus {
+			slog.Error("failed to send notification", "err", err)
+			return
+		}
+
+		n.app.QueueUpdateDraw(func() {
+			n.dbusFailed = true
+			n.pending = append(n.pending, notify.Escape(method, title, body))
+		})
+	}()
+}
+
+// Returns "all", "mentions" or "none" as configured for the channel, its parent channel or its guild, or an empty string if the settings of the official clients are followed.
+func (n *notifications) level(m discord.Message) string {
+	cfg := n.cfg.Notifications
+	if level, ok := cfg.Channels[m.ChannelID.String()]; ok {
+		return level
+	}
+
+	if c, err := discordState.Cabinet.Channel(m.ChannelID); err == nil && c.ParentID.IsValid() {
+		if level, ok := cfg.Channels[c.ParentID.String()]; ok {
+			return level
+		}
+	}
+
+	return cfg.Guilds[m.GuildID.String()]
+}
+
+func (n *notifications) shouldNotify(m discord.Message) bool {
+	if m.Author.ID == discordState.Ready().User.ID || discordState.UserIsBlocked(m.Author.ID) {
+		return false
+	}
+
+	// Dimmed messages still notify.
+	if action := layout.ignores.action(m); action == ignore.Hide || action == ignore.Collapse {
+		return false
+	}
+
+	level := n.level(m)
+	if level == "none" {
+		return false
+	}
+
+	// ningen checks the notification settings of the channel and the guild, except for the mentions of roles and the highlights.
+	if level == "" && discordState.MessageMentions(&m).Has(ningen.MessageNotifies) {
+		return true
+	}
+
+	muted := discordState.ChannelIsMuted(m.ChannelID, ningen.UnreadOpts{}) || (m.GuildID.IsValid() && discordState.MutedState.Guild(m.GuildID, false))
+	if muted {
+		return false
+	}
+
+	switch level {
+	case "all":
+		return true
+	case "mentions":
+		if !m.GuildID.IsValid() || mentionKindOf(m) != 0 {
+			return true
+		}
+	default:
+		if kind := mentionKindOf(m); kind == mentionRole || kind == mentionHighlight {
+			return true
+		}
+	}
+
+	return n.matchesKeyword(m.Content)
+}
+
+func (n *notifications) matchesKeyword(content string) bool {
+	content = strings.ToLower(content)
+	for _, k := range n.cfg.Notifications.Keywords {
+		if k != "" && strings.Contains(content, strings.ToLower(k)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the author and the channel of the message as the title and its content as the body.
+func (n *notifications) format(m discord.Message) (string, string) {
+	title := userName(n.cfg, m.Author, guildMember(m.GuildID, m.Author.ID, nil))
+	if c, err := discordState.Cabinet.Channel(m.ChannelID); err == nil && c.GuildID.IsValid() {
+		title += " in " + layout.guildsTree.channelToString(*c)
+		if g, err := discordState.Cabinet.Guild(c.GuildID); err == nil {
+			title += fmt.Sprintf(" (%s)", g.Name)
+		}
+	}
+
+	body := m.Content
+	if body == "" && len(m.Attachments) > 0 {
+		body = "[" + m.Attachments[0].Filename + "]"
+	}
+
+	if r := []rune(body); len(r) > notificationBodyLength {
+		body = string(r[:notificationBodyLength]) + "…"
+	}
+
+	return title, body
+}
+
+// Writes the pending escape sequences to the terminal.
+func (n *notifications) flush(screen tcell.Screen) {
+	if len(n.pending) == 0 {
+		return
+	}
+
+	tty, ok := screen.Tty()
+	if !ok {
+		return
+	}
+
+	for _, s := range n.pending {
+		if _, err := io.WriteString(tty, s); err != nil {
+			slog.Error("failed to write notification", "err", err)
+		}
+	}
+
+	n.pending = nil
+}
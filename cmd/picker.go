@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/0xJWLabs/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+type pickerItem struct {
+	text      string
+	reference any
+	// Added to the score of the item if it matches, to rank it higher.
+	boost int
+}
+
+// A filterable list of items with an input field on top of it.
+type picker struct {
+	*tview.Flex
+	cfg   *config.Config
+	input *tview.InputField
+	list  *tview.List
+
+	items   []pickerItem
+	matches []pickerItem
+
+	selectedFunc func(item pickerItem)
+	cancelFunc   func()
+}
+
+func newPicker(cfg *config.Config, title string) *picker {
+	p := &picker{
+		Flex:  tview.NewFlex(),
+		cfg:   cfg,
+		input: tview.NewInputField(),
+		list:  tview.NewList(),
+	}
+
+	p.input.SetChangedFunc(p.onInputChanged)
+	p.input.SetInputCapture(p.onInputCapture)
+	p.input.SetFieldBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+
+	p.list.ShowSecondaryText(false)
+	p.list.SetHighlightFullLine(true)
+	p.list.SetBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+	p.list.SetSelectedFunc(func(idx int, _, _ string, _ rune) {
+		p.selectMatch(idx)
+	})
+
+	p.SetDirection(tview.FlexRow)
+	p.AddItem(p.input, 1, 0, true)
+	p.AddItem(p.list, 0, 1, false)
+	p.SetBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+
+	p.SetTitle(title)
+	p.SetTitleColor(tcell.GetColor(cfg.Theme.TitleColor))
+	p.SetTitleAlign(tview.AlignLeft)
+	p.SetTitlePadding(1, 1)
+
+	b := cfg.Theme.BorderPadding
+	p.SetBorder(cfg.Theme.Border)
+	p.SetBorderColor(tcell.GetColor(cfg.Theme.FocusBorderColor))
+	p.SetBorderPadding(b[0], b[1], b[2], b[3])
+
+	return p
+}
+
+func (p *picker) setItems(items []pickerItem) {
+	p.items = items
+	p.onInputChanged(p.input.GetText())
+}
+
+func (p *picker) onInputChanged(text string) {
+	query := strings.ToLower(text)
+
+	type match struct {
+		item  pickerItem
+		score int
+	}
+
+	var matches []match
+	for _, item := range p.items {
+		if score, ok := fuzzyScore(strings.ToLower(item.text), query); ok {
+			matches = append(matches, match{item, score + item.boost})
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b match) int {
+		return b.score - a.score
+	})
+
+	p.matches = p.matches[:0]
+	for _, m := range matches {
+		p.matches = append(p.matches, m.item)
+	}
+
+	p.list.Clear()
+	for _, item := range p.matches {
+		p.list.AddItem(item.text, "", 0, nil)
+	}
+}
+
+func (p *picker) selectMatch(idx int) {
+	if idx < 0 || idx >= len(p.matches) || p.selectedFunc == nil {
+		return
+	}
+
+	p.selectedFunc(p.matches[idx])
+}
+
+func (p *picker) onInputCapture(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Name() {
+	case p.cfg.Keys.Picker.SelectPrevious, "Up":
+		p.list.SetCurrentItem(max(p.list.GetCurrentItem()-1, 0))
+		return nil
+	case p.cfg.Keys.Picker.SelectNext, "Down":
+		p.list.SetCurrentItem(min(p.list.GetCurrentItem()+1, p.list.GetItemCount()-1))
+		return nil
+	case p.cfg.Keys.Picker.Cancel:
+		if p.cancelFunc != nil {
+			p.cancelFunc()
+		}
+		return nil
+	case "Enter":
+		p.selectMatch(p.list.GetCurrentItem())
+		return nil
+	}
+
+	return event
+}
+
+// Reports whether the characters of the query appear in the text in order, and scores the match higher the more of them are consecutive or start a word.
+func fuzzyScore(text, query string) (int, bool) {
+	// The byte offset right after the previously matched character, or -1 before the first one.
+	score, end := 0, -1
+	for _, r := range query {
+		start := max(end, 0)
+		idx := strings.IndexRune(text[start:], r)
+		if idx == -1 {
+			return 0, false
+		}
+
+		idx += start
+		switch {
+		case idx == 0:
+			score += 3
+		case idx == end:
+			score += 2
+		case strings.ContainsRune(" _-./:(", rune(text[idx-1])):
+			score++
+		}
+
+		end = idx + utf8.RuneLen(r)
+	}
+
+	return score, true
+}
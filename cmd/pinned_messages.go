@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/0xJWLabs/tview"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/gdamore/tcell/v2"
+)
+
+// Lists the pinned messages of the selected channel, rendered like the messages text.
+type PinnedMessages struct {
+	*tview.TextView
+	cfg *config.Config
+	app *tview.Application
+
+	channelID         discord.ChannelID
+	pins              []discord.Message
+	selectedMessageID discord.MessageID
+}
+
+func newPinnedMessages(app *tview.Application, cfg *config.Config) *PinnedMessages {
+	pm := &PinnedMessages{
+		TextView: tview.NewTextView(),
+		cfg:      cfg,
+		app:      app,
+	}
+
+	pm.SetDynamicColors(true)
+	pm.SetRegions(true)
+	pm.SetWordWrap(true)
+	pm.SetInputCapture(pm.onInputCapture)
+	pm.SetHighlightedFunc(pm.onHighlighted)
+
+	pm.SetTextColor(tcell.GetColor(cfg.Theme.MessagesText.ContentColor))
+	pm.SetBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+
+	pm.SetTitle("Pinned messages")
+	pm.SetTitleColor(tcell.GetColor(cfg.Theme.TitleColor))
+	pm.SetFocusTitleColor(tcell.GetColor(cfg.Theme.FocusTitleColor))
+	pm.SetTitleAlign(tview.AlignLeft)
+	pm.SetTitlePadding(1, 1)
+
+	p := cfg.Theme.BorderPadding
+	pm.SetBorder(cfg.Theme.Border)
+	pm.SetBorderColor(tcell.GetColor(cfg.Theme.BorderColor))
+	pm.SetFocusBorderColor(tcell.GetColor(cfg.Theme.FocusBorderColor))
+	pm.SetBorderPadding(p[0], p[1], p[2], p[3])
+
+	return pm
+}
+
+// Shows the pane and fetches the pinned messages of the channel.
+func (pm *PinnedMessages) show(cID discord.ChannelID) {
+	if !cID.IsValid() {
+		return
+	}
+
+	pm.channelID = cID
+	pm.pins = nil
+	pm.selectedMessageID = 0
+	pm.Highlight()
+	pm.SetTitle("Pinned messages")
+	pm.SetText("[::d]Loading…[::-]")
+
+	layout.right.SwitchToPage(pinsPageName)
+	pm.app.SetFocus(pm)
+
+	pm.load()
+}
+
+func (pm *PinnedMessages) load() {
+	cID := pm.channelID
+	go func() {
+		pins, err := discordState.PinnedMessages(cID)
+		if err != nil {
+			slog.Error("failed to get pinned messages", "err", err, "channel_id", cID)
+			return
+		}
+
+		var gID discord.GuildID
+		if c, err := discordState.Cabinet.Channel(cID); err == nil {
+			gID = c.GuildID
+		}
+
+		// Messages fetched from the API do not have the guild ID filled.
+		for i := range pins {
+			pins[i].GuildID = gID
+		}
+
+		pm.app.QueueUpdateDraw(func() {
+			layout.messagesText.syncPinned(cID, pins)
+			if pm.channelID != cID {
+				return
+			}
+
+			pm.pins = pins
+			pm.render()
+		})
+	}()
+}
+
+// Reloads the pins if they are shown for the channel.
+func (pm *PinnedMessages) onPinsUpdate(cID discord.ChannelID) {
+	if name, _ := layout.right.GetFrontPage(); name == pinsPageName && pm.channelID == cID {
+		pm.load()
+	}
+}
+
+func (pm *PinnedMessages) render() {
+	pm.SetTitle(fmt.Sprintf("Pinned messages (%d)", len(pm.pins)))
+	if len(pm.pins) == 0 {
+		pm.SetText("[::d]No pinned messages[::-]")
+		return
+	}
+
+	mt := layout.messagesText
+	w := pm.BatchWriter()
+	defer w.Close()
+
+	w.Clear()
+	for _, m := range pm.pins {
+		mt.startRegion(w, m.ID)
+		// Pins can be old, so their date is shown even if timestamps are not.
+		if !mt.cfg.Timestamps {
+			fmt.Fprintf(w, "[::d]%s[::-] ", m.Timestamp.Time().Local().Format(searchDateFormat))
+		}
+
+		mt.createHeader(w, m, false)
+
+		mt.createBody(w, m, m.ID, false)
+		mt.endRegion(w)
+		fmt.Fprint(w, "\n\n")
+	}
+
+	if pm.selectedMessageID.IsValid() {
+		pm.Highlight(pm.selectedMessageID.String())
+	}
+}
+
+func (pm *PinnedMessages) onHighlighted(added, _, _ []string) {
+	if len(added) == 0 {
+		return
+	}
+
+	if id, err := discord.ParseSnowflake(added[0]); err == nil {
+		pm.selectedMessageID = discord.MessageID(id)
+	}
+}
+
+func (pm *PinnedMessages) selectedIndex() int {
+	return slices.IndexFunc(pm.pins, func(m discord.Message) bool {
+		return m.ID == pm.selectedMessageID
+	})
+}
+
+func (pm *PinnedMessages) onInputCapture(event *tcell.EventKey) *tcell.EventKey {
+	if len(pm.pins) == 0 && event.Name() != pm.cfg.Keys.PinnedMessages.Close {
+		return nil
+	}
+
+	idx := pm.selectedIndex()
+	switch event.Name() {
+	case pm.cfg.Keys.SelectPrevious:
+		idx = max(idx-1, 0)
+	case pm.cfg.Keys.SelectNext:
+		idx = min(idx+1, len(pm.pins)-1)
+	case pm.cfg.Keys.SelectFirst:
+		idx = 0
+	case pm.cfg.Keys.SelectLast:
+		idx = len(pm.pins) - 1
+	case pm.cfg.Keys.PinnedMessages.Unpin:
+		if idx != -1 {
+			togglePin(pm.pins[idx])
+		}
+		return nil
+	case pm.cfg.Keys.PinnedMessages.Close:
+		layout.right.SwitchToPage(messagesPageName)
+		pm.app.SetFocus(layout.messagesText)
+		return nil
+	case pm.cfg.Keys.PinnedMessages.Jump:
+		if idx != -1 {
+			m := pm.pins[idx]
+			layout.right.SwitchToPage(messagesPageName)
+			layout.messagesText.jumpToMessage(m.ChannelID, m.ID)
+		}
+		return nil
+	default:
+		return nil
+	}
+
+	pm.selectedMessageID = pm.pins[idx].ID
+	pm.Highlight(pm.selectedMessageID.String())
+	pm.ScrollToHighlight()
+	return nil
+}
+
+// Reports whether the current user can pin messages in the channel. Anyone can pin messages in direct messages.
+func canPin(cID discord.ChannelID) bool {
+	c, err := discordState.Cabinet.Channel(cID)
+	if err != nil {
+		return false
+	}
+
+	if !c.GuildID.IsValid() {
+		return true
+	}
+
+	perms, err := discordState.Permissions(cID, discordState.Ready().User.ID)
+	if err != nil {
+		slog.Error("failed to get permissions", "err", err, "channel_id", cID)
+		return false
+	}
+
+	return perms.Has(discord.PermissionManageMessages)
+}
+
+// Pins the message, or unpins it if it is pinned, if the current user is allowed to.
+func togglePin(m discord.Message) {
+	if !canPin(m.ChannelID) {
+		return
+	}
+
+	go func() {
+		var err error
+		if m.Pinned {
+			err = discordState.UnpinMessage(m.ChannelID, m.ID, "")
+		} else {
+			err = discordState.PinMessage(m.ChannelID, m.ID, "")
+		}
+
+		if err != nil {
+			slog.Error("failed to toggle pin", "err", err, "channel_id", m.ChannelID, "message_id", m.ID, "pinned", m.Pinned)
+			return
+		}
+
+		// The loaded message is not updated by the pins update event, so that toggling it again does the opposite.
+		layout.app.QueueUpdateDraw(func() {
+			layout.messagesText.updateMessageFunc(m.ID, func(old *discord.Message) {
+				old.Pinned = !m.Pinned
+			})
+		})
+	}()
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/0xJWLabs/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+const promptPageName = "prompt"
+
+// Shows a single-line input on top of the main layout. The done function is called with the trimmed text once it is submitted.
+func showPrompt(cfg *config.Config, title string, text string, done func(text string)) {
+	input := tview.NewInputField()
+	input.SetText(text)
+	input.SetFieldBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+	input.SetBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+	input.SetDoneFunc(func(key tcell.Key) {
+		layout.hideModal(promptPageName)
+
+		text := strings.TrimSpace(input.GetText())
+		if key == tcell.KeyEnter && text != "" {
+			done(text)
+		}
+	})
+
+	input.SetTitle(title)
+	input.SetTitleColor(tcell.GetColor(cfg.Theme.TitleColor))
+	input.SetTitleAlign(tview.AlignLeft)
+	input.SetTitlePadding(1, 1)
+
+	p := cfg.Theme.BorderPadding
+	input.SetBorder(cfg.Theme.Border)
+	input.SetBorderColor(tcell.GetColor(cfg.Theme.FocusBorderColor))
+	input.SetBorderPadding(p[0], p[1], p[2], p[3])
+
+	height := 1 + p[0] + p[1]
+	if cfg.Theme.Border {
+		height += 2
+	}
+
+	layout.showModal(promptPageName, input, 60, height)
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"log/slog"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/0xJWLabs/tview"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/ningen/v3"
+)
+
+const quickSwitcherPageName = "quick_switcher"
+
+// Fuzzy finds guilds, channels, threads and direct messages and selects them in the guilds tree.
+type quickSwitcher struct {
+	*picker
+}
+
+func showQuickSwitcher(cfg *config.Config) {
+	qs := &quickSwitcher{picker: newPicker(cfg, "Switch to")}
+	qs.selectedFunc = qs.onSelected
+	qs.cancelFunc = func() {
+		layout.hideModal(quickSwitcherPageName)
+	}
+
+	qs.setItems(qs.items())
+	layout.showModal(quickSwitcherPageName, qs, 60, 20)
+}
+
+// Returns the guilds and the channels that the current user can view, in the order of the guilds tree.
+func (qs *quickSwitcher) items() []pickerItem {
+	gt := layout.guildsTree
+	var items []pickerItem
+
+	cs, err := discordState.Cabinet.PrivateChannels()
+	if err != nil {
+		slog.Error("failed to get private channels", "err", err)
+	}
+
+	for _, c := range cs {
+		items = append(items, qs.channelItem("", c))
+	}
+
+	gs, err := discordState.Cabinet.Guilds()
+	if err != nil {
+		slog.Error("failed to get guilds", "err", err)
+	}
+
+	me := discordState.Ready().User.ID
+	for _, g := range gs {
+		boost := 0
+		if ind := discordState.GuildIsUnread(g.ID, ningen.GuildUnreadOpts{}); ind == ningen.ChannelMentioned {
+			boost = 4
+		}
+
+		items = append(items, pickerItem{text: tview.Escape(g.Name), reference: g.ID, boost: boost})
+
+		cs, err := discordState.Cabinet.Channels(g.ID)
+		if err != nil {
+			slog.Error("failed to get channels", "err", err, "guild_id", g.ID)
+			continue
+		}
+
+		for _, c := range cs {
+			if c.Type == discord.GuildCategory {
+				continue
+			}
+
+			// Threads inherit the permissions of their parent channel.
+			pID := c.ID
+			if isThread(c) {
+				pID = c.ParentID
+			}
+
+			if ps, err := discordState.Permissions(pID, me); err != nil || !ps.Has(discord.PermissionViewChannel) {
+				continue
+			}
+
+			prefix := g.Name + " / "
+			if isThread(c) {
+				if p, err := discordState.Cabinet.Channel(c.ParentID); err == nil {
+					prefix += gt.channelToString(*p) + " / "
+				}
+			}
+
+			items = append(items, qs.channelItem(prefix, c))
+		}
+	}
+
+	return items
+}
+
+// Ranks the channel higher the more recently it was selected and if it has unread messages or mentions.
+func (qs *quickSwitcher) channelItem(prefix string, c discord.Channel) pickerItem {
+	var boost int
+	if idx := layout.guildsTree.recents.index(c.ID); idx != -1 {
+		boost += max(10-idx/2, 1)
+	}
+
+	switch discordState.ChannelIsUnread(c.ID, ningen.UnreadOpts{}) {
+	case ningen.ChannelMentioned:
+		boost += 4
+	case ningen.ChannelUnread:
+		boost += 2
+	}
+
+	return pickerItem{text: tview.Escape(prefix + layout.guildsTree.channelToString(c)), reference: c.ID, boost: boost}
+}
+
+func (qs *quickSwitcher) onSelected(item pickerItem) {
+	layout.hideModal(quickSwitcherPageName)
+
+	gt := layout.guildsTree
+	switch ref := item.reference.(type) {
+	case discord.ChannelID:
+		gt.selectChannel(ref)
+	case discord.GuildID:
+		gt.selectGuild(ref)
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+// The number of recently selected channels that are remembered.
+const maxRecentChannels = 50
+
+// The channels that were selected most recently, latest first, kept on disk across sessions.
+type recentChannels struct {
+	path string
+	ids  []discord.ChannelID
+}
+
+func loadRecentChannels() *recentChannels {
+	rc := &recentChannels{}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		slog.Error("failed to get user cache directory", "err", err)
+		return rc
+	}
+
+	rc.path = filepath.Join(dir, config.Name, "recent_channels.json")
+	b, err := os.ReadFile(rc.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Error("failed to read recent channels", "err", err, "path", rc.path)
+		}
+
+		return rc
+	}
+
+	if err := json.Unmarshal(b, &rc.ids); err != nil {
+		slog.Error("failed to parse recent channels", "err", err, "path", rc.path)
+	}
+
+	return rc
+}
+
+// Moves the channel to the front of the recent channels and saves them.
+func (rc *recentChannels) add(cID discord.ChannelID) {
+	if idx := slices.Index(rc.ids, cID); idx != -1 {
+		rc.ids = slices.Delete(rc.ids, idx, idx+1)
+	}
+
+	rc.ids = slices.Insert(rc.ids, 0, cID)
+	rc.ids = rc.ids[:min(len(rc.ids), maxRecentChannels)]
+
+	if rc.path == "" {
+		return
+	}
+
+	b, err := json.Marshal(rc.ids)
+	if err != nil {
+		slog.Error("failed to marshal recent channels", "err", err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(rc.path), os.ModePerm); err != nil {
+		slog.Error("failed to create cache directory", "err", err)
+		return
+	}
+
+	if err := os.WriteFile(rc.path, b, 0o644); err != nil {
+		slog.Error("failed to write recent channels", "err", err, "path", rc.path)
+	}
+}
+
+// Returns how recently the channel was selected, with 0 being the latest, or -1 if it was not.
+func (rc *recentChannels) index(cID discord.ChannelID) int {
+	return slices.Index(rc.ids, cID)
+}
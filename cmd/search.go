@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/0xJWLabs/tview"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/gdamore/tcell/v2"
+)
+
+const searchFormPageName = "search_form"
+
+// The format of the dates of the before: and after: filters.
+const searchDateFormat = "2006-01-02"
+
+type searchScope string
+
+const (
+	searchChannel searchScope = "Channel"
+	searchGuild   searchScope = "Guild"
+	searchDMs     searchScope = "Direct messages"
+)
+
+// The values of the has: filter that the search endpoint accepts.
+var searchHasValues = []string{"link", "embed", "file", "image", "video", "sound", "sticker", "poll", "forward"}
+
+// Shows a form to choose the scope and enter the query, prefilled with the previous search.
+func showSearchForm(cfg *config.Config) {
+	var gID discord.GuildID
+	cID := layout.guildsTree.selectedChannelID
+	if c, err := discordState.Cabinet.Channel(cID); err == nil {
+		gID = c.GuildID
+	}
+
+	var scopes []searchScope
+	if cID.IsValid() {
+		scopes = append(scopes, searchChannel)
+	}
+	if gID.IsValid() {
+		scopes = append(scopes, searchGuild)
+	}
+	scopes = append(scopes, searchDMs)
+
+	options := make([]string, len(scopes))
+	for i, s := range scopes {
+		options[i] = string(s)
+	}
+
+	sr := layout.searchResults
+	current := slices.Index(scopes, sr.scope)
+	if current == -1 {
+		// Default to the widest scope that includes the selected channel.
+		current = min(1, len(scopes)-1)
+	}
+
+	form := tview.NewForm()
+	form.AddDropDown("Scope", options, current, nil)
+	form.AddInputField("Query", sr.query, 0, nil, nil)
+
+	submit := func() {
+		_, scope := form.GetFormItem(0).(*tview.DropDown).GetCurrentOption()
+		query := strings.TrimSpace(form.GetFormItem(1).(*tview.InputField).GetText())
+		if query == "" {
+			return
+		}
+
+		layout.hideModal(searchFormPageName)
+		sr.search(searchScope(scope), gID, cID, query)
+	}
+
+	form.GetFormItem(1).(*tview.InputField).SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			submit()
+		}
+	})
+	form.AddButton("Search", submit)
+	form.AddButton("Cancel", func() {
+		layout.hideModal(searchFormPageName)
+	})
+	form.SetCancelFunc(func() {
+		layout.hideModal(searchFormPageName)
+	})
+	form.SetFocus(1)
+
+	form.SetBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+	form.SetFieldBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+
+	form.SetTitle("Search (from: mentions: has: before: after: in:)")
+	form.SetTitleColor(tcell.GetColor(cfg.Theme.TitleColor))
+	form.SetTitleAlign(tview.AlignLeft)
+	form.SetTitlePadding(1, 1)
+	form.SetBorder(cfg.Theme.Border)
+	form.SetBorderColor(tcell.GetColor(cfg.Theme.FocusBorderColor))
+
+	layout.showModal(searchFormPageName, form, 70, 9)
+}
+
+// Parses the filters of the query into the search data. The words that are not filters are searched for.
+func parseSearchQuery(query string, gID discord.GuildID) (api.SearchData, error) {
+	var data api.SearchData
+	var content []string
+	for _, word := range strings.Fields(query) {
+		key, value, ok := strings.Cut(word, ":")
+		if !ok || value == "" {
+			content = append(content, word)
+			continue
+		}
+
+		var err error
+		switch key {
+		case "from":
+			data.AuthorID, err = findSearchUser(gID, value)
+		case "mentions":
+			data.Mentions, err = findSearchUser(gID, value)
+		case "has":
+			if !slices.Contains(searchHasValues, value) {
+				err = fmt.Errorf("unknown has: value %q", value)
+			}
+			data.Has = value
+		case "before":
+			var t time.Time
+			t, err = time.ParseInLocation(searchDateFormat, value, time.Local)
+			data.MaxID = discord.MessageID(discord.NewSnowflake(t))
+		case "after":
+			var t time.Time
+			t, err = time.ParseInLocation(searchDateFormat, value, time.Local)
+			// Only the messages after the whole day are found, like before: finds the ones before the day starts.
+			data.MinID = discord.MessageID(discord.NewSnowflake(t.AddDate(0, 0, 1)))
+		case "in":
+			data.ChannelID, err = findSearchChannel(gID, value)
+		default:
+			content = append(content, word)
+		}
+
+		if err != nil {
+			return data, err
+		}
+	}
+
+	data.Content = strings.Join(content, " ")
+	return data, nil
+}
+
+// Finds the user by mention, ID or name among the members of the guild, or the recipients of the direct messages.
+func findSearchUser(gID discord.GuildID, value string) (discord.UserID, error) {
+	value = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(strings.TrimPrefix(value, "<@"), "!"), "@"), ">")
+	if id, err := discord.ParseSnowflake(value); err == nil {
+		return discord.UserID(id), nil
+	}
+
+	matches := func(u discord.User, nick string) bool {
+		return strings.EqualFold(u.Username, value) || strings.EqualFold(u.DisplayName, value) || (nick != "" && strings.EqualFold(nick, value))
+	}
+
+	if me := discordState.Ready().User; matches(me, "") {
+		return me.ID, nil
+	}
+
+	if gID.IsValid() {
+		ms, _ := discordState.Cabinet.Members(gID)
+		for _, m := range ms {
+			if matches(m.User, m.Nick) {
+				return m.User.ID, nil
+			}
+		}
+	} else {
+		cs, _ := discordState.PrivateChannels()
+		for _, c := range cs {
+			for _, r := range c.DMRecipients {
+				if matches(r, "") {
+					return r.ID, nil
+				}
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("unknown user %q", value)
+}
+
+// Finds the channel of the guild by mention, ID or name.
+func findSearchChannel(gID discord.GuildID, value string) (discord.ChannelID, error) {
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "<#"), ">")
+	if id, err := discord.ParseSnowflake(value); err == nil {
+		return discord.ChannelID(id), nil
+	}
+
+	if !gID.IsValid() {
+		return 0, errors.New("in: is only supported in guilds")
+	}
+
+	value = strings.TrimPrefix(value, "#")
+	cs, _ := discordState.Cabinet.Channels(gID)
+	for _, c := range cs {
+		if strings.EqualFold(c.Name, value) {
+			return c.ID, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown channel %q", value)
+}
@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/0xJWLabs/tview"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/gdamore/tcell/v2"
+)
+
+// The search endpoint returns up to 25 results at a time.
+const searchPageSize = 25
+
+// Direct messages can only be searched one channel at a time, so only the most recent ones are searched.
+const searchDMChannels = 10
+
+type SearchResults struct {
+	*tview.Table
+	cfg *config.Config
+	app *tview.Application
+
+	scope     searchScope
+	query     string
+	data      api.SearchData
+	guildID   discord.GuildID
+	channelID discord.ChannelID
+
+	// The recent direct messages that are searched one after another, and the index of the one whose results are shown.
+	dmChannels []discord.ChannelID
+	dmIndex    int
+	// The direction in which the direct messages without results are skipped.
+	dmStep int
+
+	results []discord.Message
+	total   uint
+	loading bool
+	// Shown instead of the results if the search failed.
+	err error
+}
+
+func newSearchResults(app *tview.Application, cfg *config.Config) *SearchResults {
+	sr := &SearchResults{
+		Table: tview.NewTable(),
+		cfg:   cfg,
+		app:   app,
+	}
+
+	sr.SetSelectable(true, false)
+	sr.SetSelectedFunc(sr.onSelected)
+	sr.SetInputCapture(sr.onInputCapture)
+	sr.SetBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+
+	sr.SetTitle("Search")
+	sr.SetTitleColor(tcell.GetColor(cfg.Theme.TitleColor))
+	sr.SetFocusTitleColor(tcell.GetColor(cfg.Theme.FocusTitleColor))
+	sr.SetTitleAlign(tview.AlignLeft)
+	sr.SetTitlePadding(1, 1)
+
+	p := cfg.Theme.BorderPadding
+	sr.SetBorder(cfg.Theme.Border)
+	sr.SetBorderColor(tcell.GetColor(cfg.Theme.BorderColor))
+	sr.SetFocusBorderColor(tcell.GetColor(cfg.Theme.FocusBorderColor))
+	sr.SetBorderPadding(p[0], p[1], p[2], p[3])
+
+	return sr
+}
+
+// Parses the query, shows the results pane and loads the first page of results.
+func (sr *SearchResults) search(scope searchScope, gID discord.GuildID, cID discord.ChannelID, query string) {
+	sr.scope = scope
+	sr.query = query
+	sr.guildID = gID
+	sr.channelID = cID
+	sr.results = nil
+	sr.total = 0
+	// The results of a previous search that is still loading are discarded.
+	sr.loading = false
+
+	// Users are looked up among the recipients of the direct messages instead of the members of the guild.
+	filterGuildID := gID
+	if scope == searchDMs {
+		filterGuildID = 0
+	}
+
+	sr.data, sr.err = parseSearchQuery(query, filterGuildID)
+	sr.data.IncludeNSFW = true
+
+	sr.dmChannels, sr.dmIndex, sr.dmStep = nil, 0, 1
+	if scope == searchDMs && sr.err == nil {
+		sr.dmChannels = recentDMChannels()
+		if len(sr.dmChannels) == 0 {
+			sr.err = errors.New("no direct messages to search")
+		}
+	}
+
+	layout.right.SwitchToPage(searchPageName)
+	sr.app.SetFocus(sr)
+
+	if sr.err != nil {
+		sr.draw()
+		return
+	}
+
+	sr.load(0)
+}
+
+func (sr *SearchResults) load(offset uint) {
+	if sr.loading {
+		return
+	}
+
+	sr.loading = true
+	sr.data.Offset = offset
+	sr.draw()
+
+	scope, data, gID, cID := sr.scope, sr.data, sr.guildID, sr.channelID
+	if scope == searchDMs {
+		gID, cID = 0, sr.dmChannels[sr.dmIndex]
+	}
+
+	go func() {
+		results, total, err := searchMessages(scope, data, gID, cID)
+		sr.app.QueueUpdateDraw(func() {
+			// Another search may have been started in the meantime.
+			if sr.scope != scope || sr.data != data || (scope == searchDMs && (sr.dmIndex >= len(sr.dmChannels) || sr.dmChannels[sr.dmIndex] != cID)) {
+				return
+			}
+
+			sr.loading = false
+			sr.results, sr.total, sr.err = results, total, err
+			if err != nil {
+				slog.Error("failed to search messages", "err", err, "query", sr.query)
+			}
+
+			if err == nil && total == 0 && sr.skipDMChannel() {
+				return
+			}
+
+			sr.draw()
+			sr.Select(0, 0)
+			sr.ScrollToBeginning()
+		})
+	}()
+}
+
+// Returns the page of messages that match the search, from latest to oldest, and the total number of matches. Direct messages are searched in the channel.
+func searchMessages(scope searchScope, data api.SearchData, gID discord.GuildID, cID discord.ChannelID) ([]discord.Message, uint, error) {
+	var resp api.SearchResponse
+	var err error
+	switch scope {
+	case searchChannel, searchDMs:
+		data.ChannelID = cID
+		if gID.IsValid() {
+			resp, err = discordState.Search(gID, data)
+		} else {
+			resp, err = discordState.SearchDirectMessages(data)
+		}
+	case searchGuild:
+		resp, err = discordState.Search(gID, data)
+	}
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var results []discord.Message
+	for _, ms := range resp.Messages {
+		// The match may be returned along with the messages around it.
+		if len(ms) > 0 {
+			results = append(results, ms[len(ms)/2])
+		}
+	}
+
+	slices.SortFunc(results, func(a, b discord.Message) int {
+		return cmp.Compare(b.ID, a.ID)
+	})
+
+	return results, resp.TotalResults, nil
+}
+
+// Returns the most recently active direct messages, from latest to oldest.
+func recentDMChannels() []discord.ChannelID {
+	cs, err := discordState.PrivateChannels()
+	if err != nil {
+		slog.Error("failed to get private channels", "err", err)
+		return nil
+	}
+
+	cs = slices.DeleteFunc(slices.Clone(cs), func(c discord.Channel) bool {
+		return !c.LastMessageID.IsValid()
+	})
+	slices.SortFunc(cs, func(a, b discord.Channel) int {
+		return cmp.Compare(b.LastMessageID, a.LastMessageID)
+	})
+
+	ids := make([]discord.ChannelID, 0, min(len(cs), searchDMChannels))
+	for _, c := range cs[:min(len(cs), searchDMChannels)] {
+		ids = append(ids, c.ID)
+	}
+
+	return ids
+}
+
+// Moves on to the first page of the next direct messages in the direction of dmStep, and turns around at the most recent ones. Returns false if there are none left to search.
+func (sr *SearchResults) skipDMChannel() bool {
+	if sr.scope != searchDMs {
+		return false
+	}
+
+	next := sr.dmIndex + sr.dmStep
+	if next < 0 {
+		sr.dmStep = 1
+		next = sr.dmIndex + 1
+	}
+
+	if next >= len(sr.dmChannels) {
+		return false
+	}
+
+	sr.dmIndex = next
+	sr.load(0)
+	return true
+}
+
+func (sr *SearchResults) draw() {
+	sr.Clear()
+
+	switch {
+	case sr.err != nil:
+		sr.SetTitle("Search: " + sr.err.Error())
+		return
+	case sr.loading:
+		sr.SetTitle("Search: loading…")
+		return
+	case len(sr.results) == 0:
+		sr.SetTitle("Search: no results")
+		return
+	}
+
+	offset := sr.data.Offset
+	title := fmt.Sprintf("Search: %s (%d–%d of %d", sr.query, offset+1, offset+uint(len(sr.results)), sr.total)
+	if sr.scope == searchDMs {
+		title += fmt.Sprintf(" in direct messages %d of %d", sr.dmIndex+1, len(sr.dmChannels))
+	}
+	sr.SetTitle(title + ")")
+
+	theme := sr.cfg.Theme.MessagesText
+	for i, m := range sr.results {
+		channel := m.ChannelID.String()
+		if c, err := discordState.Cabinet.Channel(m.ChannelID); err == nil {
+			channel = layout.guildsTree.channelToString(*c)
+		}
+
+		name := userName(sr.cfg, m.Author, guildMember(sr.guildID, m.Author.ID, nil))
+		content, _, _ := strings.Cut(m.Content, "\n")
+		if content == "" && len(m.Attachments) > 0 {
+			content = "[" + m.Attachments[0].Filename + "]"
+		}
+
+		sr.SetCell(i, 0, tview.NewTableCell(tview.Escape(channel)).SetReference(m).SetMaxWidth(20))
+		sr.SetCell(i, 1, tview.NewTableCell(tview.Escape(name)).SetTextColor(tcell.GetColor(theme.AuthorColor)).SetMaxWidth(20))
+		sr.SetCell(i, 2, tview.NewTableCell(m.Timestamp.Time().Local().Format(searchDateFormat)).SetAttributes(tcell.AttrDim))
+		sr.SetCell(i, 3, tview.NewTableCell(tview.Escape(content)).SetExpansion(1))
+	}
+}
+
+func (sr *SearchResults) onSelected(row, _ int) {
+	m, ok := sr.GetCell(row, 0).GetReference().(discord.Message)
+	if !ok {
+		return
+	}
+
+	layout.right.SwitchToPage(messagesPageName)
+	layout.messagesText.jumpToMessage(m.ChannelID, m.ID)
+}
+
+func (sr *SearchResults) onInputCapture(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Name() {
+	case sr.cfg.Keys.SelectPrevious:
+		return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+	case sr.cfg.Keys.SelectNext:
+		return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+	case sr.cfg.Keys.SelectFirst:
+		return tcell.NewEventKey(tcell.KeyHome, 0, tcell.ModNone)
+	case sr.cfg.Keys.SelectLast:
+		return tcell.NewEventKey(tcell.KeyEnd, 0, tcell.ModNone)
+	case sr.cfg.Keys.SearchResults.NextPage:
+		if next := sr.data.Offset + searchPageSize; next < sr.total {
+			sr.load(next)
+		} else if !sr.loading {
+			sr.dmStep = 1
+			sr.skipDMChannel()
+		}
+		return nil
+	case sr.cfg.Keys.SearchResults.PreviousPage:
+		if sr.data.Offset > 0 {
+			sr.load(sr.data.Offset - min(sr.data.Offset, searchPageSize))
+		} else if !sr.loading && sr.dmIndex > 0 {
+			sr.dmStep = -1
+			sr.skipDMChannel()
+		}
+		return nil
+	case sr.cfg.Keys.SearchResults.Close:
+		layout.right.SwitchToPage(messagesPageName)
+		sr.app.SetFocus(layout.messagesText)
+		return nil
+	}
+
+	return event
+}
@@ -10,9 +10,10 @@ import (
 	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
 	"github.com/diamondburned/arikawa/v3/utils/httputil/httpdriver"
 	"github.com/diamondburned/ningen/v3"
-	"github.com/gdamore/tcell/v2"
+	"github.com/diamondburned/ningen/v3/states/read"
 	"github.com/0xJWLabs/tview"
 )
 
@@ -45,12 +46,70 @@ func openState(token string, app *tview.Application, cfg *config.Config) error {
 	// Handlers
 	discordState.AddHandler(discordState.onReady)
 	discordState.AddHandler(discordState.onMessageCreate)
+	discordState.AddHandler(discordState.onMessageUpdate)
 	discordState.AddHandler(discordState.onMessageDelete)
+	discordState.AddHandler(discordState.onThreadCreate)
+	discordState.AddHandler(discordState.onThreadUpdate)
+	discordState.AddHandler(discordState.onThreadDelete)
+	discordState.AddHandler(discordState.onMessageReactionAdd)
+	discordState.AddHandler(discordState.onMessageReactionRemove)
+	discordState.AddHandler(discordState.onMessageReactionRemoveAll)
+	discordState.AddHandler(discordState.onMessageReactionRemoveEmoji)
+	discordState.AddHandler(discordState.onReadUpdate)
+	discordState.AddHandler(discordState.onTypingStart)
+	discordState.AddHandler(discordState.onGuildMemberListUpdate)
+	discordState.AddHandler(discordState.onGuildMembersChunk)
+	discordState.AddHandler(discordState.onRelationshipAdd)
+	discordState.AddHandler(discordState.onRelationshipRemove)
+	discordState.AddHandler(discordState.onUserGuildSettingsUpdate)
+	discordState.AddHandler(discordState.onChannelPinsUpdate)
+	discordState.AddHandler(discordState.onChannelUpdate)
 
 	discordState.OnRequest = append(discordState.Client.OnRequest, discordState.onRequest)
 	return discordState.Open(context.TODO())
 }
 
+// Marks the channels as read up to their latest messages with a single bulk acknowledgement.
+func (s *State) markRead(cIDs []discord.ChannelID) {
+	type readState struct {
+		ChannelID discord.ChannelID `json:"channel_id"`
+		MessageID discord.MessageID `json:"message_id"`
+	}
+
+	var rss []readState
+	for _, cID := range cIDs {
+		mID := s.LastMessage(cID)
+		if !mID.IsValid() {
+			continue
+		}
+
+		// Channels without a read state have never been read.
+		if rs := s.ReadState.ReadState(cID); rs != nil && rs.LastMessageID >= mID && rs.MentionCount == 0 {
+			continue
+		}
+
+		// ningen's MarkRead updates the read state and acks the channel on its own if it has the message of another user cached, so only the others are acked in bulk.
+		if m, err := s.Cabinet.Message(cID, mID); err != nil || m.Author.ID == s.Ready().User.ID {
+			rss = append(rss, readState{cID, mID})
+		}
+
+		s.ReadState.MarkRead(cID, mID)
+	}
+
+	go func() {
+		// The endpoint accepts up to 100 read states at a time.
+		for chunk := range slices.Chunk(rss, 100) {
+			body := struct {
+				ReadStates []readState `json:"read_states"`
+			}{chunk}
+
+			if err := s.FastRequest("POST", api.Endpoint+"read-states/ack-bulk", httputil.WithJSONBody(body)); err != nil {
+				slog.Error("failed to ack channels", "err", err)
+			}
+		}
+	}()
+}
+
 func (s *State) onRequest(r httpdriver.Request) error {
 	req, ok := r.(*httpdriver.DefaultRequest)
 	if ok {
@@ -62,9 +121,16 @@ func (s *State) onRequest(r httpdriver.Request) error {
 
 func (s *State) onReady(r *gateway.ReadyEvent) {
 	root := layout.guildsTree.GetRoot()
+	mentionsNode := tview.NewTreeNode("Mentions")
+	mentionsNode.SetReference(mentionsNodeRef{})
+	root.AddChild(mentionsNode)
+	layout.guildsTree.mentionsNode = mentionsNode
+	layout.mentionsInbox.backfill()
+
 	dmNode := tview.NewTreeNode("Direct Messages")
-	dmNode.SetColor(tcell.GetColor(s.cfg.Theme.GuildsTree.PrivateChannelColor))
 	root.AddChild(dmNode)
+	layout.guildsTree.dmNode = dmNode
+	layout.guildsTree.updateDMNode()
 
 	// Track guilds that have a parent (folder) to add orphan channels later
 	var folderGuildIds []discord.GuildID
@@ -90,17 +156,180 @@ func (s *State) onReady(r *gateway.ReadyEvent) {
 }
 
 func (s *State) onMessageCreate(m *gateway.MessageCreateEvent) {
-	if layout.guildsTree.selectedChannelID.IsValid() && layout.guildsTree.selectedChannelID == m.ChannelID {
-		layout.messagesText.createMessage(m.Message)
-	}
+	s.app.QueueUpdateDraw(func() {
+		layout.highlights.onMessageCreate(m.Message)
+		layout.mentionsInbox.onMessageCreate(m.Message)
+		layout.notifications.onMessageCreate(m.Message)
+
+		if layout.guildsTree.selectedChannelID.IsValid() && layout.guildsTree.selectedChannelID == m.ChannelID {
+			layout.typingIndicator.removeTyper(m.Author.ID)
+
+			mt := layout.messagesText
+			// The message is loaded along with the history after the older message that was jumped to.
+			if mt.detached {
+				return
+			}
+
+			// The new message is seen only if the previous one was.
+			seen := mt.isScrolledToEnd()
+
+			mt.messages = append([]discord.Message{m.Message}, mt.messages...)
+			mt.createMessage(mt, m.Message)
+
+			if seen {
+				mt.ackLatest()
+			}
+		}
+	})
+}
+
+func (s *State) onMessageUpdate(m *gateway.MessageUpdateEvent) {
+	s.app.QueueUpdateDraw(func() {
+		if layout.guildsTree.selectedChannelID == m.ChannelID {
+			layout.messagesText.updateMessage(m.Message)
+		}
+	})
 }
 
 func (s *State) onMessageDelete(m *gateway.MessageDeleteEvent) {
-	if layout.guildsTree.selectedChannelID == m.ChannelID {
-		layout.messagesText.selectedMessageID = 0
-		layout.messagesText.Highlight()
-		layout.messagesText.Clear()
+	s.app.QueueUpdateDraw(func() {
+		if layout.guildsTree.selectedChannelID == m.ChannelID {
+			layout.messagesText.selectedMessageID = 0
+			layout.messagesText.Highlight()
+
+			layout.messagesText.removeMessage(m.ID)
+			layout.messagesText.render()
+		}
+	})
+}
+
+func (s *State) onMessageReactionAdd(r *gateway.MessageReactionAddEvent) {
+	s.app.QueueUpdateDraw(func() {
+		if layout.guildsTree.selectedChannelID == r.ChannelID {
+			layout.messagesText.addReaction(r.MessageID, r.Emoji, r.UserID == s.Ready().User.ID)
+		}
+	})
+}
+
+func (s *State) onMessageReactionRemove(r *gateway.MessageReactionRemoveEvent) {
+	s.app.QueueUpdateDraw(func() {
+		if layout.guildsTree.selectedChannelID == r.ChannelID {
+			layout.messagesText.removeReaction(r.MessageID, r.Emoji, r.UserID == s.Ready().User.ID)
+		}
+	})
+}
 
-		layout.messagesText.drawMsgs(m.ChannelID)
+func (s *State) onMessageReactionRemoveAll(r *gateway.MessageReactionRemoveAllEvent) {
+	s.app.QueueUpdateDraw(func() {
+		if layout.guildsTree.selectedChannelID == r.ChannelID {
+			layout.messagesText.clearReactions(r.MessageID, nil)
+		}
+	})
+}
+
+func (s *State) onMessageReactionRemoveEmoji(r *gateway.MessageReactionRemoveEmojiEvent) {
+	s.app.QueueUpdateDraw(func() {
+		if layout.guildsTree.selectedChannelID == r.ChannelID {
+			layout.messagesText.clearReactions(r.MessageID, &r.Emoji)
+		}
+	})
+}
+
+// Updates the header and the input of the selected channel after its topic, slowmode or permissions change.
+func (s *State) onChannelUpdate(c *gateway.ChannelUpdateEvent) {
+	s.app.QueueUpdateDraw(func() {
+		if layout.guildsTree.selectedChannelID == c.ID {
+			layout.channelHeader.setChannel(c.Channel)
+			layout.messageInput.setChannel(c.Channel)
+		}
+	})
+}
+
+func (s *State) onChannelPinsUpdate(p *gateway.ChannelPinsUpdateEvent) {
+	s.app.QueueUpdateDraw(func() {
+		layout.pinnedMessages.onPinsUpdate(p.ChannelID)
+	})
+}
+
+func (s *State) onThreadCreate(t *gateway.ThreadCreateEvent) {
+	s.app.QueueUpdateDraw(func() {
+		layout.guildsTree.onThreadCreate(t.Channel)
+	})
+}
+
+func (s *State) onThreadUpdate(t *gateway.ThreadUpdateEvent) {
+	s.app.QueueUpdateDraw(func() {
+		if t.ThreadMetadata != nil && t.ThreadMetadata.Archived {
+			layout.guildsTree.onThreadDelete(t.ID)
+		} else {
+			layout.guildsTree.onThreadCreate(t.Channel)
+		}
+	})
+}
+
+func (s *State) onThreadDelete(t *gateway.ThreadDeleteEvent) {
+	s.app.QueueUpdateDraw(func() {
+		layout.guildsTree.onThreadDelete(t.ID)
+	})
+}
+
+// Called by ningen whenever a channel is marked as read or unread, which includes new messages and acks from other clients.
+func (s *State) onReadUpdate(r *read.UpdateEvent) {
+	s.app.QueueUpdateDraw(func() {
+		layout.guildsTree.onReadUpdate(r.ChannelID, r.GuildID)
+	})
+}
+
+func (s *State) onUserGuildSettingsUpdate(u *gateway.UserGuildSettingsUpdateEvent) {
+	s.app.QueueUpdateDraw(func() {
+		layout.guildsTree.onGuildSettingsUpdate(u.GuildID)
+	})
+}
+
+func (s *State) onTypingStart(t *gateway.TypingStartEvent) {
+	if !s.cfg.ShowTypingIndicator || t.UserID == s.Ready().User.ID {
+		return
+	}
+
+	s.app.QueueUpdateDraw(func() {
+		if layout.guildsTree.selectedChannelID == t.ChannelID {
+			layout.typingIndicator.onTypingStart(t)
+		}
+	})
+}
+
+// Called after ningen has applied the update to its copy of the member list.
+func (s *State) onGuildMemberListUpdate(ev *gateway.GuildMemberListUpdateEvent) {
+	s.app.QueueUpdateDraw(func() {
+		layout.memberList.onListUpdate(ev)
+		layout.channelHeader.onListUpdate(ev.GuildID)
+	})
+}
+
+// Messages of blocked users are hidden, so the messages are redrawn once a user is blocked or unblocked.
+func (s *State) onRelationshipAdd(r *gateway.RelationshipAddEvent) {
+	if r.Type == discord.BlockedRelationship {
+		s.app.QueueUpdateDraw(layout.messagesText.onBlockedUsersChange)
 	}
 }
+
+func (s *State) onRelationshipRemove(r *gateway.RelationshipRemoveEvent) {
+	s.app.QueueUpdateDraw(layout.messagesText.onBlockedUsersChange)
+}
+
+// Members that were not cached when the messages were drawn are requested, so their names and colors are updated once they arrive.
+func (s *State) onGuildMembersChunk(c *gateway.GuildMembersChunkEvent) {
+	s.app.QueueUpdateDraw(func() {
+		mt := layout.messagesText
+		if !layout.guildsTree.selectedChannelID.IsValid() || len(mt.messages) == 0 || mt.messages[0].GuildID != c.GuildID {
+			return
+		}
+
+		for _, m := range c.Members {
+			if slices.ContainsFunc(mt.messages, func(msg discord.Message) bool { return msg.Author.ID == m.User.ID }) {
+				mt.render()
+				return
+			}
+		}
+	})
+}
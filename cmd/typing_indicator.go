@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/0xJWLabs/tview"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/gdamore/tcell/v2"
+)
+
+// Discord stops showing a user as typing if no typing event was received from them for this long.
+const typingTimeout = 10 * time.Second
+
+type typer struct {
+	userID discord.UserID
+	name   string
+	timer  *time.Timer
+}
+
+// A single line that lists the users typing in the selected channel.
+type TypingIndicator struct {
+	*tview.TextView
+	cfg *config.Config
+	app *tview.Application
+
+	typers []typer
+}
+
+func newTypingIndicator(app *tview.Application, cfg *config.Config) *TypingIndicator {
+	ti := &TypingIndicator{
+		TextView: tview.NewTextView(),
+		cfg:      cfg,
+		app:      app,
+	}
+
+	ti.SetDynamicColors(true)
+	ti.SetWrap(false)
+	ti.SetTextColor(tcell.GetColor(cfg.Theme.MessagesText.ContentColor))
+	ti.SetBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+	ti.SetBorderPadding(0, 0, 1, 1)
+
+	return ti
+}
+
+func (ti *TypingIndicator) reset() {
+	for _, t := range ti.typers {
+		t.timer.Stop()
+	}
+
+	ti.typers = nil
+	ti.Clear()
+}
+
+func (ti *TypingIndicator) onTypingStart(t *gateway.TypingStartEvent) {
+	if idx := slices.IndexFunc(ti.typers, func(t2 typer) bool { return t2.userID == t.UserID }); idx != -1 {
+		ti.typers[idx].timer.Reset(typingTimeout)
+		return
+	}
+
+	cID := t.ChannelID
+	timer := time.AfterFunc(typingTimeout, func() {
+		ti.app.QueueUpdateDraw(func() {
+			if layout.guildsTree.selectedChannelID == cID {
+				ti.removeTyper(t.UserID)
+			}
+		})
+	})
+
+	ti.typers = append(ti.typers, typer{userID: t.UserID, name: ti.typerName(t), timer: timer})
+	ti.draw()
+}
+
+// Removes the user from the list, for example once their message arrives.
+func (ti *TypingIndicator) removeTyper(uID discord.UserID) {
+	idx := slices.IndexFunc(ti.typers, func(t typer) bool { return t.userID == uID })
+	if idx == -1 {
+		return
+	}
+
+	ti.typers[idx].timer.Stop()
+	ti.typers = slices.Delete(ti.typers, idx, idx+1)
+	ti.draw()
+}
+
+func (ti *TypingIndicator) typerName(t *gateway.TypingStartEvent) string {
+	if m := guildMember(t.GuildID, t.UserID, t.Member); m != nil {
+		return userName(ti.cfg, m.User, m)
+	}
+
+	// Direct messages
+	if c, err := discordState.Cabinet.Channel(t.ChannelID); err == nil {
+		for _, r := range c.DMRecipients {
+			if r.ID == t.UserID {
+				return userName(ti.cfg, r, nil)
+			}
+		}
+	}
+
+	return "Someone"
+}
+
+func (ti *TypingIndicator) draw() {
+	var text string
+	switch n := len(ti.typers); {
+	case n == 0:
+	case n == 1:
+		text = fmt.Sprintf("[::b]%s[::-] is typing…", tview.Escape(ti.typers[0].name))
+	case n <= 3:
+		var names string
+		for i, t := range ti.typers[:n-1] {
+			if i > 0 {
+				names += ", "
+			}
+
+			names += "[::b]" + tview.Escape(t.name) + "[::-]"
+		}
+
+		text = fmt.Sprintf("%s and [::b]%s[::-] are typing…", names, tview.Escape(ti.typers[n-1].name))
+	default:
+		text = "Several people are typing…"
+	}
+
+	ti.SetText(text)
+}
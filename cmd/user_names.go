@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/state"
+)
+
+// Returns the member from the cabinet, or the partial member sent along with a message or mention if it is not cached.
+func guildMember(gID discord.GuildID, uID discord.UserID, partial *discord.Member) *discord.Member {
+	if !gID.IsValid() {
+		return nil
+	}
+
+	if m, err := discordState.Cabinet.Member(gID, uID); err == nil {
+		return m
+	}
+
+	// The messages are redrawn once the member arrives.
+	discordState.MemberState.RequestMember(gID, uID)
+	return partial
+}
+
+// Returns the name of the user according to the configured name format. The member is nil outside of guilds.
+func userName(cfg *config.Config, u discord.User, m *discord.Member) string {
+	switch cfg.NameFormat {
+	case "username":
+		return u.Username
+	case "nickname":
+		if m != nil && m.Nick != "" {
+			return m.Nick
+		}
+	}
+
+	return u.DisplayOrUsername()
+}
+
+// Returns the color of the name of the member if role colors are enabled and the member has a colored role, and the fallback otherwise.
+func userColor(cfg *config.Config, gID discord.GuildID, m *discord.Member, fallback string) string {
+	if !cfg.RoleColors || m == nil {
+		return fallback
+	}
+
+	if c, ok := memberColor(gID, *m); ok {
+		return c.String()
+	}
+
+	return fallback
+}
+
+// Returns the color of the highest role of the member that has one.
+func memberColor(gID discord.GuildID, m discord.Member) (discord.Color, bool) {
+	return state.MemberColor(&m, func(rID discord.RoleID) *discord.Role {
+		r, _ := discordState.Cabinet.Role(gID, rID)
+		return r
+	})
+}
@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/0xJWLabs/tview"
+	"github.com/atotto/clipboard"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"github.com/gdamore/tcell/v2"
+)
+
+const userProfilePageName = "user_profile"
+
+// The part of the response of the profile endpoint that is not known from the gateway.
+type userProfileResponse struct {
+	MutualGuilds []struct {
+		ID discord.GuildID `json:"id"`
+	} `json:"mutual_guilds"`
+}
+
+type userProfile struct {
+	*tview.Flex
+	cfg  *config.Config
+	app  *tview.Application
+	text *tview.TextView
+	form *tview.Form
+
+	guildID discord.GuildID
+	user    discord.User
+	// Nil until the profile is fetched.
+	mutualGuilds []discord.GuildID
+}
+
+// Shows the profile of the user, including their membership of the guild if the guild ID is valid.
+func showUserProfile(cfg *config.Config, gID discord.GuildID, u discord.User) {
+	up := &userProfile{
+		Flex: tview.NewFlex(),
+		cfg:  cfg,
+		app:  layout.app,
+		text: tview.NewTextView(),
+		form: tview.NewForm(),
+
+		guildID: gID,
+		user:    u,
+	}
+
+	up.text.SetDynamicColors(true)
+	up.text.SetWordWrap(true)
+	up.text.SetBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+
+	up.form.AddButton("Message", up.openDM)
+	up.form.AddButton("Mention", up.mention)
+	up.form.AddButton("Copy ID", up.copyID)
+	up.form.AddButton(ternary(discordState.UserIsBlocked(u.ID), "Unblock", "Block"), up.toggleBlock)
+	up.form.AddButton("Ignore", up.ignore)
+	up.form.SetButtonsAlign(tview.AlignCenter)
+	up.form.SetBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+	up.form.SetBorderPadding(0, 0, 0, 0)
+	up.form.SetCancelFunc(up.hide)
+
+	up.SetDirection(tview.FlexRow)
+	up.AddItem(up.text, 0, 1, false)
+	up.AddItem(up.form, 1, 0, true)
+	up.SetBackgroundColor(tcell.GetColor(cfg.Theme.BackgroundColor))
+
+	up.SetTitle("Profile")
+	up.SetTitleColor(tcell.GetColor(cfg.Theme.TitleColor))
+	up.SetTitleAlign(tview.AlignLeft)
+	up.SetTitlePadding(1, 1)
+
+	p := cfg.Theme.BorderPadding
+	up.SetBorder(cfg.Theme.Border)
+	up.SetBorderColor(tcell.GetColor(cfg.Theme.FocusBorderColor))
+	up.SetBorderPadding(p[0], p[1], p[2], p[3])
+
+	up.draw()
+	layout.showModal(userProfilePageName, up, 64, 24)
+
+	go up.fetchMutualGuilds()
+}
+
+func (up *userProfile) hide() {
+	layout.hideModal(userProfilePageName)
+}
+
+func (up *userProfile) fetchMutualGuilds() {
+	var resp userProfileResponse
+	err := discordState.RequestJSON(
+		&resp, "GET",
+		api.EndpointUsers+up.user.ID.String()+"/profile",
+		httputil.WithSchema(discordState.Client, url.Values{"with_mutual_guilds": {"true"}}),
+	)
+	if err != nil {
+		slog.Error("failed to get user profile", "err", err, "user_id", up.user.ID)
+		return
+	}
+
+	up.app.QueueUpdateDraw(func() {
+		up.mutualGuilds = make([]discord.GuildID, 0, len(resp.MutualGuilds))
+		for _, g := range resp.MutualGuilds {
+			up.mutualGuilds = append(up.mutualGuilds, g.ID)
+		}
+
+		up.draw()
+	})
+}
+
+func (up *userProfile) draw() {
+	u := up.user
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[::b]%s[::-]\n", tview.Escape(u.DisplayOrUsername()))
+	fmt.Fprintf(&b, "[::d]@%s[::-]\n", tview.Escape(u.Username))
+
+	up.createPresence(&b)
+
+	fmt.Fprintf(&b, "\nID: %s\n", u.ID)
+	fmt.Fprintf(&b, "Avatar: %s\n", u.AvatarURL())
+	fmt.Fprintf(&b, "Account created: %s\n", u.CreatedAt().Local().Format("2 Jan 2006"))
+
+	if up.guildID.IsValid() {
+		up.createMember(&b)
+	}
+
+	if up.mutualGuilds != nil {
+		var names []string
+		for _, gID := range up.mutualGuilds {
+			if g, err := discordState.Cabinet.Guild(gID); err == nil {
+				names = append(names, tview.Escape(g.Name))
+			}
+		}
+
+		fmt.Fprintf(&b, "\nMutual guilds (%d): %s\n", len(names), strings.Join(names, ", "))
+	}
+
+	if note := discordState.NoteState.Note(u.ID); note != "" {
+		fmt.Fprintf(&b, "\nNote: %s\n", tview.Escape(note))
+	}
+
+	up.text.SetText(b.String())
+}
+
+func (up *userProfile) createPresence(w io.Writer) {
+	p, err := discordState.Cabinet.Presence(up.guildID, up.user.ID)
+	if err != nil {
+		fmt.Fprintf(w, "[%s]● offline[-]\n", up.cfg.Theme.MemberList.OfflineColor)
+		return
+	}
+
+	fmt.Fprintf(w, "[%s]● %s[-]\n", layout.memberList.statusColor(p.Status), ternary(p.Status == "", string(discord.OfflineStatus), string(p.Status)))
+	for _, a := range p.Activities {
+		var text string
+		switch a.Type {
+		case discord.GameActivity:
+			text = "Playing " + a.Name
+		case discord.StreamingActivity:
+			text = "Streaming " + a.Details
+		case discord.ListeningActivity:
+			text = "Listening to " + a.Name
+		case discord.WatchingActivity:
+			text = "Watching " + a.Name
+		case discord.CustomActivity:
+			text = a.State
+			if a.Emoji != nil {
+				text = strings.TrimSpace(emojiToString(*a.Emoji) + " " + text)
+			}
+		case discord.CompetingActivity:
+			text = "Competing in " + a.Name
+		}
+
+		if text != "" {
+			fmt.Fprintf(w, "%s\n", tview.Escape(text))
+		}
+	}
+}
+
+func (up *userProfile) createMember(w io.Writer) {
+	m, err := discordState.Cabinet.Member(up.guildID, up.user.ID)
+	if err != nil {
+		return
+	}
+
+	if m.Nick != "" {
+		fmt.Fprintf(w, "Nickname: %s\n", tview.Escape(m.Nick))
+	}
+
+	fmt.Fprintf(w, "Joined guild: %s\n", m.Joined.Time().Local().Format("2 Jan 2006"))
+
+	var roles []string
+	for _, rID := range m.RoleIDs {
+		r, err := discordState.Cabinet.Role(up.guildID, rID)
+		if err != nil {
+			continue
+		}
+
+		color := up.cfg.Theme.MemberList.MemberColor
+		if r.Color > 0 {
+			color = r.Color.String()
+		}
+
+		roles = append(roles, fmt.Sprintf("[%s]%s[-]", color, tview.Escape(r.Name)))
+	}
+
+	if len(roles) > 0 {
+		fmt.Fprintf(w, "\nRoles: %s\n", strings.Join(roles, ", "))
+	}
+}
+
+func (up *userProfile) openDM() {
+	up.hide()
+
+	uID := up.user.ID
+	go func() {
+		c, err := discordState.CreatePrivateChannel(uID)
+		if err != nil {
+			slog.Error("failed to create private channel", "err", err, "user_id", uID)
+			return
+		}
+
+		if err := discordState.Cabinet.ChannelSet(c, false); err != nil {
+			slog.Error("failed to set private channel", "err", err, "channel_id", c.ID)
+		}
+
+		up.app.QueueUpdateDraw(func() {
+			gt := layout.guildsTree
+			// The nodes of direct messages are otherwise only created the first time their node is selected.
+			if dmNode := gt.dmNode; dmNode != nil && len(dmNode.GetChildren()) != 0 && findNode(dmNode, c.ID) == nil {
+				gt.createChannelNode(dmNode, *c)
+			}
+
+			gt.selectChannel(c.ID)
+		})
+	}()
+}
+
+func (up *userProfile) mention() {
+	up.hide()
+
+	mi := layout.messageInput
+	text := mi.GetText()
+	if text != "" && !strings.HasSuffix(text, " ") {
+		text += " "
+	}
+
+	mi.setText(text + up.user.Mention() + " ")
+	up.app.SetFocus(mi)
+}
+
+func (up *userProfile) copyID() {
+	up.hide()
+
+	if err := clipboard.WriteAll(up.user.ID.String()); err != nil {
+		slog.Error("failed to write to clipboard", "err", err)
+	}
+}
+
+func (up *userProfile) ignore() {
+	up.hide()
+	showIgnoreForm(up.cfg, up.guildID, layout.guildsTree.selectedChannelID, up.user)
+}
+
+func (up *userProfile) toggleBlock() {
+	up.hide()
+
+	uID := up.user.ID
+	blocked := discordState.UserIsBlocked(uID)
+	go func() {
+		var err error
+		if blocked {
+			err = discordState.DeleteRelationship(uID)
+		} else {
+			err = discordState.SetRelationship(uID, discord.BlockedRelationship)
+		}
+
+		if err != nil {
+			slog.Error("failed to update relationship", "err", err, "user_id", uID, "blocked", blocked)
+		}
+	}()
+}
@@ -16,41 +16,115 @@ type Config struct {
 
 	MessagesLimit uint8  `toml:"messages_limit"`
 	Editor        string `toml:"editor"`
+	// Whether channels are marked as read once their latest message is seen.
+	AutoAck bool `toml:"auto_ack"`
+
+	ShowTypingIndicator bool `toml:"show_typing_indicator"`
+	SendTypingIndicator bool `toml:"send_typing_indicator"`
 
 	Timestamps       bool   `toml:"timestamps"`
 	TimestampsFormat string `toml:"timestamps_format"`
 
 	ShowAttachmentLinks bool `toml:"show_attachment_links"`
 
+	// Which name of users is shown: "username", "display_name" or "nickname".
+	NameFormat string `toml:"name_format"`
+	// Whether the names of guild members are colored by their highest colored role instead of the theme.
+	RoleColors bool `toml:"role_colors"`
+
+	Downloads     Downloads     `toml:"downloads"`
+	ImagePreviews ImagePreviews `toml:"image_previews"`
+	Notifications Notifications `toml:"notifications"`
+	// The rules that highlight the messages whose content matches them.
+	Highlights []Highlight `toml:"highlights"`
+
 	Keys  Keys  `toml:"keys"`
 	Theme Theme `toml:"theme"`
 }
 
+type Downloads struct {
+	// The directory attachments are saved to. Defaults to the Downloads directory in the home directory.
+	Dir string `toml:"dir"`
+	// The commands that downloaded files are opened with, keyed by MIME type ("image/png"), its type ("image/*") or "*". The path of the file is appended to the command.
+	OpenWith map[string]string `toml:"open_with"`
+}
+
+type ImagePreviews struct {
+	Enabled bool `toml:"enabled"`
+	// Either "auto", which detects the protocol from the environment, "kitty", "sixel" or "halfblock".
+	Protocol string `toml:"protocol"`
+	// The maximum number of lines a preview takes up.
+	MaxHeight int `toml:"max_height"`
+	// The terminals, by their TERM_PROGRAM or TERM, in which previews are not shown even if enabled.
+	DisabledTerminals []string `toml:"disabled_terminals"`
+}
+
+type Notifications struct {
+	Enabled bool `toml:"enabled"`
+	// Either "auto", which uses D-Bus and falls back to the OSC 9 escape sequence and the bell, "dbus", "osc9", "osc777" or "bell".
+	Method string `toml:"method"`
+	// Messages that contain any of the keywords, ignoring case, notify like mentions.
+	Keywords []string `toml:"keywords"`
+	// Which messages notify in the guilds and channels, by their ID: "all", "mentions" (the default, which includes direct messages and keywords) or "none". Channels take precedence over their guild, and threads over their parent channel.
+	Guilds   map[string]string `toml:"guilds"`
+	Channels map[string]string `toml:"channels"`
+}
+
+type Highlight struct {
+	// A regular expression in the syntax of https://golang.org/s/re2syntax. Prefix it with (?i) to ignore case.
+	Pattern string `toml:"pattern"`
+	// The IDs of the guild and of the channel the rule is limited to, if set. Rules limited to a channel also apply to its threads, and rules limited to a category to the channels in it, but not to the threads of those channels.
+	GuildID   string `toml:"guild_id"`
+	ChannelID string `toml:"channel_id"`
+}
+
 func defaultConfig() *Config {
 	return &Config{
 		Mouse:            true,
 		HideBlockedUsers: true,
 		MessagesLimit:    50,
 		Editor:           "default",
+		AutoAck:          true,
+
+		ShowTypingIndicator: true,
+		SendTypingIndicator: true,
 
 		Timestamps:       false,
 		TimestampsFormat: time.Kitchen,
 
 		ShowAttachmentLinks: true,
 
+		NameFormat: "nickname",
+		RoleColors: true,
+
+		ImagePreviews: ImagePreviews{
+			Protocol:  "auto",
+			MaxHeight: 12,
+		},
+
+		Notifications: Notifications{
+			Enabled: true,
+			Method:  "auto",
+		},
+
 		Keys:  defaultKeys(),
 		Theme: defaultTheme(),
 	}
 }
 
-// Reads the configuration file and parses it.
-func Load() (*Config, error) {
+// Returns the directory of the configuration file, which other files of the client are saved next to.
+func Dir() string {
 	path, err := os.UserConfigDir()
 	if err != nil {
 		path = "."
 	}
 
-	path = filepath.Join(path, Name, "config.toml")
+	return filepath.Join(path, Name)
+}
+
+// Reads the configuration file and parses it.
+func Load() (*Config, error) {
+	path := filepath.Join(Dir(), "config.toml")
 	f, err := os.Open(path)
 
 	cfg := defaultConfig()
@@ -6,15 +6,24 @@ type (
 		FocusMessagesText string `toml:"focus_messages_text"`
 		FocusMessageInput string `toml:"focus_message_input"`
 		ToggleGuildsTree  string `toml:"toggle_guilds_tree"`
+		ToggleMemberList  string `toml:"toggle_member_list"`
+		Search            string `toml:"search"`
+		GoTo              string `toml:"go_to"`
+		QuickSwitcher     string `toml:"quick_switcher"`
 
 		SelectPrevious string `toml:"select_previous"`
 		SelectNext     string `toml:"select_next"`
 		SelectFirst    string `toml:"select_first"`
 		SelectLast     string `toml:"select_last"`
 
-		GuildsTree   GuildsTreeKeys   `toml:"guilds_tree"`
-		MessagesText MessagesTextKeys `toml:"messages_text"`
-		MessageInput MessageInputKeys `toml:"message_input"`
+		GuildsTree     GuildsTreeKeys     `toml:"guilds_tree"`
+		MessagesText   MessagesTextKeys   `toml:"messages_text"`
+		MessageInput   MessageInputKeys   `toml:"message_input"`
+		ForumView      ForumViewKeys      `toml:"forum_view"`
+		SearchResults  SearchResultsKeys  `toml:"search_results"`
+		PinnedMessages PinnedMessagesKeys `toml:"pinned_messages"`
+		MentionsInbox  MentionsInboxKeys  `toml:"mentions_inbox"`
+		Picker         PickerKeys         `toml:"picker"`
 
 		Logout string `toml:"logout"`
 		Quit   string `toml:"quit"`
@@ -22,23 +31,73 @@ type (
 
 	GuildsTreeKeys struct {
 		SelectCurrent string `toml:"select_current"`
+		MarkRead      string `toml:"mark_read"`
+		MarkAllRead   string `toml:"mark_all_read"`
 	}
 
 	MessagesTextKeys struct {
 		SelectReply  string `toml:"select_reply"`
 		SelectPin    string `toml:"select_pin"`
+		SelectUnread string `toml:"select_unread"`
 		Reply        string `toml:"reply"`
 		ReplyMention string `toml:"reply_mention"`
 
-		Delete string `toml:"delete"`
-		Yank   string `toml:"yank"`
-		Open   string `toml:"open"`
+		React    string `toml:"react"`
+		Edit     string `toml:"edit"`
+		Delete   string `toml:"delete"`
+		Yank     string `toml:"yank"`
+		Open     string `toml:"open"`
+		OpenLink string `toml:"open_link"`
+
+		Download        string `toml:"download"`
+		CancelDownloads string `toml:"cancel_downloads"`
+
+		ShowProfile string `toml:"show_profile"`
+		ShowPins    string `toml:"show_pins"`
+		TogglePin   string `toml:"toggle_pin"`
+
+		Ignore          string `toml:"ignore"`
+		ToggleCollapsed string `toml:"toggle_collapsed"`
+
+		OpenThread   string `toml:"open_thread"`
+		CreateThread string `toml:"create_thread"`
 	}
 
 	MessageInputKeys struct {
 		Send   string `toml:"send"`
 		Editor string `toml:"editor"`
 		Cancel string `toml:"cancel"`
+
+		Attach           string `toml:"attach"`
+		RemoveAttachment string `toml:"remove_attachment"`
+	}
+
+	ForumViewKeys struct {
+		ToggleSort string `toml:"toggle_sort"`
+		CreatePost string `toml:"create_post"`
+	}
+
+	SearchResultsKeys struct {
+		NextPage     string `toml:"next_page"`
+		PreviousPage string `toml:"previous_page"`
+		Close        string `toml:"close"`
+	}
+
+	PinnedMessagesKeys struct {
+		Jump  string `toml:"jump"`
+		Unpin string `toml:"unpin"`
+		Close string `toml:"close"`
+	}
+
+	MentionsInboxKeys struct {
+		CycleFilter string `toml:"cycle_filter"`
+		Close       string `toml:"close"`
+	}
+
+	PickerKeys struct {
+		SelectPrevious string `toml:"select_previous"`
+		SelectNext     string `toml:"select_next"`
+		Cancel         string `toml:"cancel"`
 	}
 )
 
@@ -48,6 +107,10 @@ func defaultKeys() Keys {
 		FocusMessagesText: "Ctrl+T",
 		FocusMessageInput: "Ctrl+P",
 		ToggleGuildsTree:  "Ctrl+B",
+		ToggleMemberList:  "Ctrl+L",
+		Search:            "Ctrl+S",
+		GoTo:              "Ctrl+N",
+		QuickSwitcher:     "Ctrl+K",
 
 		Logout: "Ctrl+D",
 		Quit:   "Ctrl+C",
@@ -59,24 +122,74 @@ func defaultKeys() Keys {
 
 		GuildsTree: GuildsTreeKeys{
 			SelectCurrent: "Enter",
+			MarkRead:      "Rune[m]",
+			MarkAllRead:   "Rune[M]",
 		},
 
 		MessagesText: MessagesTextKeys{
-			SelectReply: "Rune[s]",
-			SelectPin:   "Rune[p]",
+			SelectReply:  "Rune[s]",
+			SelectPin:    "Rune[p]",
+			SelectUnread: "Rune[u]",
 
 			Reply:        "Rune[r]",
 			ReplyMention: "Rune[R]",
 
-			Delete: "Rune[d]",
-			Yank:   "Rune[y]",
-			Open:   "Rune[o]",
+			React:    "Rune[+]",
+			Edit:     "Rune[e]",
+			Delete:   "Rune[d]",
+			Yank:     "Rune[y]",
+			Open:     "Rune[o]",
+			OpenLink: "Rune[O]",
+
+			Download:        "Rune[w]",
+			CancelDownloads: "Rune[W]",
+
+			ShowProfile: "Rune[i]",
+			ShowPins:    "Rune[P]",
+			TogglePin:   "Alt+Rune[p]",
+
+			Ignore:          "Rune[I]",
+			ToggleCollapsed: "Rune[z]",
+
+			OpenThread:   "Rune[t]",
+			CreateThread: "Rune[T]",
 		},
 
 		MessageInput: MessageInputKeys{
 			Send:   "Enter",
 			Editor: "Ctrl+E",
 			Cancel: "Esc",
+
+			Attach:           "Ctrl+O",
+			RemoveAttachment: "Ctrl+R",
+		},
+
+		ForumView: ForumViewKeys{
+			ToggleSort: "Rune[s]",
+			CreatePost: "Rune[n]",
+		},
+
+		SearchResults: SearchResultsKeys{
+			NextPage:     "Rune[n]",
+			PreviousPage: "Rune[p]",
+			Close:        "Esc",
+		},
+
+		PinnedMessages: PinnedMessagesKeys{
+			Jump:  "Enter",
+			Unpin: "Alt+Rune[p]",
+			Close: "Esc",
+		},
+
+		MentionsInbox: MentionsInboxKeys{
+			CycleFilter: "Rune[f]",
+			Close:       "Esc",
+		},
+
+		Picker: PickerKeys{
+			SelectPrevious: "Ctrl+P",
+			SelectNext:     "Ctrl+N",
+			Cancel:         "Esc",
 		},
 	}
 }
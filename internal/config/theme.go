@@ -15,6 +15,21 @@ type (
 
 		GuildsTree   GuildsTreeTheme   `toml:"guilds_tree"`
 		MessagesText MessagesTextTheme `toml:"messages_text"`
+		MemberList   MemberListTheme   `toml:"member_list"`
+	}
+
+	MemberListTheme struct {
+		// The number of columns the member list takes up.
+		Width int `toml:"width"`
+		// Either "left" (next to the guilds tree) or "right".
+		Position string `toml:"position"`
+
+		GroupColor        string `toml:"group_color"`
+		MemberColor       string `toml:"member_color"`
+		OnlineColor       string `toml:"online_color"`
+		IdleColor         string `toml:"idle_color"`
+		DoNotDisturbColor string `toml:"do_not_disturb_color"`
+		OfflineColor      string `toml:"offline_color"`
 	}
 
 	GuildsTreeTheme struct {
@@ -23,6 +38,10 @@ type (
 		Graphics            bool   `toml:"graphics"`
 		GuildColor          string `toml:"guild_color"`
 		PrivateChannelColor string `toml:"private_channel_color"`
+
+		UnreadColor  string `toml:"unread_color"`
+		MentionColor string `toml:"mention_color"`
+		MutedColor   string `toml:"muted_color"`
 	}
 
 	MessagesTextTheme struct {
@@ -34,6 +53,20 @@ type (
 		EmojiColor      string `toml:"emoji_color"`
 		LinkColor       string `toml:"link_color"`
 		AttachmentColor string `toml:"attachment_color"`
+
+		ThreadColor       string `toml:"thread_color"`
+		ReactionColor     string `toml:"reaction_color"`
+		ReactionSelfColor string `toml:"reaction_self_color"`
+		NewMessagesColor  string `toml:"new_messages_color"`
+		HighlightColor    string `toml:"highlight_color"`
+
+		EmbedBorderColor      string `toml:"embed_border_color"`
+		EmbedAuthorColor      string `toml:"embed_author_color"`
+		EmbedTitleColor       string `toml:"embed_title_color"`
+		EmbedDescriptionColor string `toml:"embed_description_color"`
+		EmbedFieldNameColor   string `toml:"embed_field_name_color"`
+		EmbedFieldValueColor  string `toml:"embed_field_value_color"`
+		EmbedFooterColor      string `toml:"embed_footer_color"`
 	}
 )
 
@@ -54,6 +87,10 @@ func defaultTheme() Theme {
 			Graphics:            true,
 			GuildColor:          tview.Styles.PrimaryTextColor.String(),
 			PrivateChannelColor: tview.Styles.PrimaryTextColor.String(),
+
+			UnreadColor:  "white",
+			MentionColor: "red",
+			MutedColor:   "gray",
 		},
 		MessagesText: MessagesTextTheme{
 			ReplyIndicator: string(tview.BoxDrawingsLightArcDownAndRight) + " ",
@@ -64,6 +101,31 @@ func defaultTheme() Theme {
 			EmojiColor:      "green",
 			LinkColor:       "blue",
 			AttachmentColor: "yellow",
+
+			ThreadColor:       "aqua",
+			ReactionColor:     "gray",
+			ReactionSelfColor: "teal",
+			NewMessagesColor:  "red",
+			HighlightColor:    "yellow",
+
+			EmbedBorderColor:      "gray",
+			EmbedAuthorColor:      tview.Styles.PrimaryTextColor.String(),
+			EmbedTitleColor:       "blue",
+			EmbedDescriptionColor: tview.Styles.PrimaryTextColor.String(),
+			EmbedFieldNameColor:   tview.Styles.PrimaryTextColor.String(),
+			EmbedFieldValueColor:  tview.Styles.PrimaryTextColor.String(),
+			EmbedFooterColor:      "gray",
+		},
+		MemberList: MemberListTheme{
+			Width:    28,
+			Position: "right",
+
+			GroupColor:        "gray",
+			MemberColor:       tview.Styles.PrimaryTextColor.String(),
+			OnlineColor:       "green",
+			IdleColor:         "yellow",
+			DoNotDisturbColor: "red",
+			OfflineColor:      "gray",
 		},
 	}
 }
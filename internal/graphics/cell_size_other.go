@@ -0,0 +1,8 @@
+//go:build !unix
+
+package graphics
+
+// Returns the size of a cell of the terminal in pixels.
+func CellSize() (width, height int) {
+	return defaultCellWidth, defaultCellHeight
+}
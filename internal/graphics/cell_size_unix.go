@@ -0,0 +1,19 @@
+//go:build unix
+
+package graphics
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// Returns the size of a cell of the terminal in pixels.
+func CellSize() (width, height int) {
+	ws, err := unix.IoctlGetWinsize(int(os.Stdout.Fd()), unix.TIOCGWINSZ)
+	if err != nil || ws.Col == 0 || ws.Row == 0 || ws.Xpixel == 0 || ws.Ypixel == 0 {
+		return defaultCellWidth, defaultCellHeight
+	}
+
+	return int(ws.Xpixel / ws.Col), int(ws.Ypixel / ws.Row)
+}
@@ -0,0 +1,60 @@
+// Package graphics draws images in terminals, either with a graphics protocol or with colored half blocks.
+package graphics
+
+import (
+	"os"
+	"slices"
+	"strings"
+)
+
+type Protocol string
+
+const (
+	Kitty     Protocol = "kitty"
+	Sixel     Protocol = "sixel"
+	HalfBlock Protocol = "halfblock"
+)
+
+// Terminals that are known to support the protocols, by their TERM or TERM_PROGRAM.
+var (
+	kittyTerminals = []string{"xterm-kitty", "kitty", "WezTerm", "ghostty", "xterm-ghostty"}
+	sixelTerminals = []string{"foot", "foot-extra", "mlterm", "contour", "iTerm.app", "yaft-256color"}
+)
+
+// Returns the name of the terminal as reported by TERM_PROGRAM, or TERM if it is not set.
+func Terminal() string {
+	if term := os.Getenv("TERM_PROGRAM"); term != "" {
+		return term
+	}
+
+	return os.Getenv("TERM")
+}
+
+// Detects the protocol the terminal supports from the environment, falling back to half blocks.
+func Detect() Protocol {
+	term, program := os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")
+	if os.Getenv("KITTY_WINDOW_ID") != "" {
+		return Kitty
+	}
+
+	supports := func(terminals []string) bool {
+		return slices.Contains(terminals, term) || slices.Contains(terminals, program) || slices.ContainsFunc(terminals, func(t string) bool {
+			return strings.HasPrefix(term, t+"-")
+		})
+	}
+
+	switch {
+	case supports(kittyTerminals):
+		return Kitty
+	case supports(sixelTerminals):
+		return Sixel
+	default:
+		return HalfBlock
+	}
+}
+
+// The size of a cell in pixels if the terminal does not report it.
+const (
+	defaultCellWidth  = 10
+	defaultCellHeight = 20
+)
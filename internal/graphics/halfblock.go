@@ -0,0 +1,36 @@
+package graphics
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+// Returns the lines of upper half blocks that draw the image with tview color tags, two rows of pixels per line. The image is scaled to the columns and twice the rows.
+func HalfBlocks(img image.Image, cols, rows int) []string {
+	scaled := Scale(img, cols, rows*2)
+
+	lines := make([]string, rows)
+	for row := range rows {
+		var b strings.Builder
+		var prev string
+		for x := range cols {
+			// Transparent pixels are blended with black since the colors are premultiplied.
+			top := scaled.RGBAAt(x, row*2)
+			bottom := scaled.RGBAAt(x, row*2+1)
+
+			tag := fmt.Sprintf("[#%02x%02x%02x:#%02x%02x%02x]", top.R, top.G, top.B, bottom.R, bottom.G, bottom.B)
+			if tag != prev {
+				b.WriteString(tag)
+				prev = tag
+			}
+
+			b.WriteRune('▀')
+		}
+
+		b.WriteString("[-:-]")
+		lines[row] = b.String()
+	}
+
+	return lines
+}
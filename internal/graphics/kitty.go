@@ -0,0 +1,63 @@
+package graphics
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+)
+
+// The maximum size of the payload of a single escape sequence.
+const kittyChunkSize = 4096
+
+// Transmits the image to the terminal with the ID without displaying it.
+func TransmitKitty(w io.Writer, id uint32, img image.Image) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+	for first := true; first || data != ""; first = false {
+		chunk := data[:min(kittyChunkSize, len(data))]
+		data = data[len(chunk):]
+
+		more := 0
+		if data != "" {
+			more = 1
+		}
+
+		var err error
+		if first {
+			_, err = fmt.Fprintf(w, "\x1b_Ga=t,f=100,i=%d,q=2,m=%d;%s\x1b\\", id, more, chunk)
+		} else {
+			_, err = fmt.Fprintf(w, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Displays the transmitted image at the cursor, scaled to the columns and rows, without moving the cursor.
+func PlaceKitty(w io.Writer, id uint32, cols, rows int) error {
+	_, err := fmt.Fprintf(w, "\x1b_Ga=p,i=%d,c=%d,r=%d,C=1,q=2\x1b\\", id, cols, rows)
+	return err
+}
+
+// Removes every displayed image while keeping the transmitted ones.
+func ClearKitty(w io.Writer) error {
+	_, err := io.WriteString(w, "\x1b_Ga=d,d=a,q=2\x1b\\")
+	return err
+}
+
+// Removes every displayed image and frees the transmitted ones.
+func DeleteKitty(w io.Writer) error {
+	_, err := io.WriteString(w, "\x1b_Ga=d,d=A,q=2\x1b\\")
+	return err
+}
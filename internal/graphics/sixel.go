@@ -0,0 +1,99 @@
+package graphics
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+)
+
+// The number of levels of each channel of the color cube that images are quantized to.
+const sixelLevels = 6
+
+// Encodes the image as sixels with a 216-color palette.
+func WriteSixel(w io.Writer, img *image.RGBA) error {
+	bw := bufio.NewWriter(w)
+	b := img.Bounds()
+
+	// Start the sequence with a 1:1 pixel aspect ratio and the raster attributes.
+	fmt.Fprintf(bw, "\x1bP0;1;0q\"1;1;%d;%d", b.Dx(), b.Dy())
+	for i := range sixelLevels * sixelLevels * sixelLevels {
+		r, g, bl := i/(sixelLevels*sixelLevels), i/sixelLevels%sixelLevels, i%sixelLevels
+		fmt.Fprintf(bw, "#%d;2;%d;%d;%d", i, r*100/(sixelLevels-1), g*100/(sixelLevels-1), bl*100/(sixelLevels-1))
+	}
+
+	index := func(x, y int) int {
+		c := img.RGBAAt(x, y)
+		if c.A < 0x80 {
+			return -1
+		}
+
+		level := func(v uint8) int { return (int(v)*(sixelLevels-1) + 127) / 255 }
+		return level(c.R)*sixelLevels*sixelLevels + level(c.G)*sixelLevels + level(c.B)
+	}
+
+	used := make([]bool, sixelLevels*sixelLevels*sixelLevels)
+	for y0 := b.Min.Y; y0 < b.Max.Y; y0 += 6 {
+		clear(used)
+		for y := y0; y < min(y0+6, b.Max.Y); y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				if i := index(x, y); i != -1 {
+					used[i] = true
+				}
+			}
+		}
+
+		for i, ok := range used {
+			if !ok {
+				continue
+			}
+
+			fmt.Fprintf(bw, "#%d", i)
+			writeSixelRow(bw, b, y0, func(x, y int) bool { return index(x, y) == i })
+			// Carriage return to draw the next color over the same band.
+			bw.WriteByte('$')
+		}
+
+		// Move to the next band.
+		bw.WriteByte('-')
+	}
+
+	bw.WriteString("\x1b\\")
+	return bw.Flush()
+}
+
+// Writes the band of six rows of pixels starting at y0, run-length encoded, with the pixels that match set.
+func writeSixelRow(w *bufio.Writer, b image.Rectangle, y0 int, match func(x, y int) bool) {
+	var prev byte
+	var run int
+	flush := func() {
+		switch {
+		case run > 3:
+			fmt.Fprintf(w, "!%d%c", run, prev)
+		case run > 0:
+			for range run {
+				w.WriteByte(prev)
+			}
+		}
+	}
+
+	for x := b.Min.X; x < b.Max.X; x++ {
+		var bits byte
+		for dy := range 6 {
+			if y := y0 + dy; y < b.Max.Y && match(x, y) {
+				bits |= 1 << dy
+			}
+		}
+
+		c := '?' + bits
+		if c == prev {
+			run++
+			continue
+		}
+
+		flush()
+		prev, run = c, 1
+	}
+
+	flush()
+}
@@ -0,0 +1,84 @@
+// Package highlight matches the content of messages against the highlight rules of the configuration.
+package highlight
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+type rule struct {
+	re *regexp.Regexp
+	// Zero if the rule applies to all guilds or channels.
+	guildID   discord.GuildID
+	channelID discord.ChannelID
+}
+
+// Matches messages against a set of rules. The zero value matches nothing.
+type Matcher struct {
+	rules []rule
+}
+
+// Compiles the rules. The rules that are invalid are skipped and reported in the returned error.
+func New(highlights []config.Highlight) (*Matcher, error) {
+	var m Matcher
+	var errs []error
+	for i, h := range highlights {
+		re, err := regexp.Compile(h.Pattern)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("highlight %d: %w", i, err))
+			continue
+		}
+
+		r := rule{re: re}
+		if h.GuildID != "" {
+			id, err := discord.ParseSnowflake(h.GuildID)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("highlight %d: invalid guild ID: %w", i, err))
+				continue
+			}
+
+			r.guildID = discord.GuildID(id)
+		}
+
+		if h.ChannelID != "" {
+			id, err := discord.ParseSnowflake(h.ChannelID)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("highlight %d: invalid channel ID: %w", i, err))
+				continue
+			}
+
+			r.channelID = discord.ChannelID(id)
+		}
+
+		m.rules = append(m.rules, r)
+	}
+
+	return &m, errors.Join(errs...)
+}
+
+// Reports whether the content matches any of the rules that apply to the guild and the channel. Rules scoped to a channel also apply to its threads, whose parent is given as parentID.
+func (m *Matcher) Match(gID discord.GuildID, cID, parentID discord.ChannelID, content string) bool {
+	if m == nil || content == "" {
+		return false
+	}
+
+	for _, r := range m.rules {
+		if r.guildID.IsValid() && r.guildID != gID {
+			continue
+		}
+
+		if r.channelID.IsValid() && r.channelID != cID && r.channelID != parentID {
+			continue
+		}
+
+		if r.re.MatchString(content) {
+			return true
+		}
+	}
+
+	return false
+}
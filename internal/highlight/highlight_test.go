@@ -0,0 +1,113 @@
+package highlight
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/0xJWLabs/discordo/internal/config"
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name       string
+		highlights []config.Highlight
+		rules      int
+		errs       []string
+	}{
+		{
+			name:       "valid",
+			highlights: []config.Highlight{{Pattern: "foo"}, {Pattern: "(?i)bar", GuildID: "1", ChannelID: "2"}},
+			rules:      2,
+		},
+		{
+			name:       "invalid pattern",
+			highlights: []config.Highlight{{Pattern: "("}, {Pattern: "foo"}},
+			rules:      1,
+			errs:       []string{"highlight 0:"},
+		},
+		{
+			name:       "invalid IDs",
+			highlights: []config.Highlight{{Pattern: "foo", GuildID: "guild"}, {Pattern: "bar", ChannelID: "channel"}, {Pattern: "baz"}},
+			rules:      1,
+			errs:       []string{"highlight 0: invalid guild ID", "highlight 1: invalid channel ID"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := New(tt.highlights)
+			if m == nil {
+				t.Fatal("New returned a nil matcher")
+			}
+
+			if len(m.rules) != tt.rules {
+				t.Errorf("got %d rules, want %d", len(m.rules), tt.rules)
+			}
+
+			if len(tt.errs) == 0 && err != nil {
+				t.Errorf("got error %v, want none", err)
+			}
+
+			for _, want := range tt.errs {
+				if err == nil || !strings.Contains(err.Error(), want) {
+					t.Errorf("got error %v, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	const (
+		guildID      discord.GuildID   = 1
+		otherGuildID discord.GuildID   = 2
+		categoryID   discord.ChannelID = 10
+		channelID    discord.ChannelID = 11
+		threadID     discord.ChannelID = 12
+		otherID      discord.ChannelID = 13
+	)
+
+	m, err := New([]config.Highlight{
+		{Pattern: `(?i)\bdeploy\b`},
+		{Pattern: "guild", GuildID: "1"},
+		{Pattern: "channel", ChannelID: "11"},
+		{Pattern: "category", ChannelID: "10"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		gID      discord.GuildID
+		cID      discord.ChannelID
+		parentID discord.ChannelID
+		content  string
+		want     bool
+	}{
+		{"any channel", otherGuildID, otherID, 0, "Deploy now", true},
+		{"no match", guildID, channelID, 0, "deployment", false},
+		{"empty content", guildID, channelID, 0, "", false},
+		{"guild scope", guildID, otherID, 0, "guild", true},
+		{"other guild", otherGuildID, otherID, 0, "guild", false},
+		{"channel scope", guildID, channelID, categoryID, "channel", true},
+		{"other channel", guildID, otherID, 0, "channel", false},
+		{"thread of channel", guildID, threadID, channelID, "channel", true},
+		{"channel in category", guildID, channelID, categoryID, "category", true},
+		{"thread in category", guildID, threadID, channelID, "category", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.Match(tt.gID, tt.cID, tt.parentID, tt.content); got != tt.want {
+				t.Errorf("Match(%d, %d, %d, %q) = %v, want %v", tt.gID, tt.cID, tt.parentID, tt.content, got, tt.want)
+			}
+		})
+	}
+
+	var zero *Matcher
+	if zero.Match(guildID, channelID, 0, "deploy") {
+		t.Error("nil matcher matched")
+	}
+}
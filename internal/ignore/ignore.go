@@ -0,0 +1,164 @@
+// Package ignore matches messages against the client-side ignore rules, which are saved to a file next to the configuration file.
+package ignore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"github.com/BurntSushi/toml"
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+type Action string
+
+const (
+	// The message is not shown at all.
+	Hide Action = "hide"
+	// The message is shown as a line that can be expanded.
+	Collapse Action = "collapse"
+	// The message is shown dimmed.
+	Dim Action = "dim"
+)
+
+var Actions = []Action{Hide, Collapse, Dim}
+
+// A rule matches the messages that match all of its fields that are set.
+type Rule struct {
+	UserID discord.UserID `toml:"user_id,omitzero"`
+	RoleID discord.RoleID `toml:"role_id,omitzero"`
+	// Matches the messages of bots and webhooks.
+	Bots bool `toml:"bots,omitempty"`
+	// A regular expression that the content of the message matches.
+	Pattern string `toml:"pattern,omitempty"`
+	// Also matches the threads of the channel and the channels of a category.
+	ChannelID discord.ChannelID `toml:"channel_id,omitzero"`
+
+	Action Action `toml:"action"`
+}
+
+// Reports whether the rules match the same messages.
+func (r Rule) sameAs(other Rule) bool {
+	r.Action, other.Action = "", ""
+	return r == other
+}
+
+type List struct {
+	path  string
+	Rules []Rule `toml:"rules"`
+	// The compiled patterns of the rules, keyed by pattern.
+	patterns map[string]*regexp.Regexp
+	// Why the file could not be decoded. Only some of its rules may have been read then, so it is not saved over.
+	loadErr error
+}
+
+// Loads the rules from the file at the path. A file that does not exist yet is an empty list. If the file cannot be decoded, the rules that were read are used but not saved.
+func Load(path string) (*List, error) {
+	l := &List{path: path}
+	if _, err := toml.DecodeFile(path, l); err != nil && !errors.Is(err, os.ErrNotExist) {
+		l.loadErr = err
+		return l, errors.Join(err, l.compile())
+	}
+
+	return l, l.compile()
+}
+
+func (l *List) compile() error {
+	l.patterns = make(map[string]*regexp.Regexp)
+	var errs []error
+	for _, r := range l.Rules {
+		if r.Pattern == "" {
+			continue
+		}
+
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		l.patterns[r.Pattern] = re
+	}
+
+	return errors.Join(errs...)
+}
+
+// Writes the rules to the file, unless it failed to load.
+func (l *List) Save() error {
+	if l.loadErr != nil {
+		return fmt.Errorf("not overwriting %s since it failed to load: %w", l.path, l.loadErr)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), os.ModePerm); err != nil {
+		return err
+	}
+
+	f, err := os.Create(l.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return toml.NewEncoder(f).Encode(l)
+}
+
+// Adds the rule, or replaces the action of the rule that matches the same messages.
+func (l *List) Set(rule Rule) error {
+	if idx := slices.IndexFunc(l.Rules, rule.sameAs); idx != -1 {
+		l.Rules[idx] = rule
+	} else {
+		l.Rules = append(l.Rules, rule)
+	}
+
+	return l.compile()
+}
+
+// Removes the rule that matches the same messages, if any.
+func (l *List) Remove(rule Rule) {
+	l.Rules = slices.DeleteFunc(l.Rules, rule.sameAs)
+}
+
+// Returns the rule that matches the same messages, if any.
+func (l *List) Find(rule Rule) (Rule, bool) {
+	idx := slices.IndexFunc(l.Rules, rule.sameAs)
+	if idx == -1 {
+		return Rule{}, false
+	}
+
+	return l.Rules[idx], true
+}
+
+// Returns the action of the first rule that matches the message, or an empty string if none does. The roles of the author and the parent of the channel are not part of the message, so they are given separately.
+func (l *List) Match(m discord.Message, roleIDs []discord.RoleID, parentID discord.ChannelID) Action {
+	for _, r := range l.Rules {
+		if r.UserID.IsValid() && r.UserID != m.Author.ID {
+			continue
+		}
+
+		if r.RoleID.IsValid() && !slices.Contains(roleIDs, r.RoleID) {
+			continue
+		}
+
+		if r.Bots && !m.Author.Bot && !m.WebhookID.IsValid() {
+			continue
+		}
+
+		if r.ChannelID.IsValid() && r.ChannelID != m.ChannelID && r.ChannelID != parentID {
+			continue
+		}
+
+		if r.Pattern != "" {
+			re, ok := l.patterns[r.Pattern]
+			if !ok || !re.MatchString(m.Content) {
+				continue
+			}
+		}
+
+		return r.Action
+	}
+
+	return ""
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/0xJWLabs/tview"
+	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/ningen/v3/discordmd"
 	"github.com/yuin/goldmark/ast"
 	gmr "github.com/yuin/goldmark/renderer"
@@ -11,6 +13,12 @@ import (
 
 var DefaultRenderer = newRenderer()
 
+// Returns the name and the color of a mentioned user or role. It can be set with the "userMention" and "roleMention" options.
+type MentionFunc[T any] func(gID discord.GuildID, v T) (name string, color string)
+
+// Returns the tags that are written around a link to the URL, for example to make it a region. It is given the writer passed to Render, so that it can depend on what is being rendered. It can be set with the "linkTags" option.
+type LinkTagsFunc func(w io.Writer, url string) (before string, after string)
+
 type renderer struct {
 	config *gmr.Config
 }
@@ -20,6 +28,11 @@ func newRenderer() *renderer {
 	return &renderer{config}
 }
 
+// NewRenderer returns a renderer with its own options, for views that need options the default renderer must not share.
+func NewRenderer() gmr.Renderer {
+	return newRenderer()
+}
+
 // AddOptions implements renderer.Renderer.
 func (r *renderer) AddOptions(opts ...gmr.Option) {
 	for _, opt := range opts {
@@ -46,19 +59,21 @@ func (r *renderer) Render(w io.Writer, source []byte, n ast.Node) error {
 				}
 			}
 		case *ast.AutoLink:
+			before, after := r.linkTags(w, string(n.URL(source)))
 			if entering {
 				linkColor := r.config.Options["linkColor"].(string)
-				io.WriteString(w, "["+linkColor+"]")
+				io.WriteString(w, before+"["+linkColor+"]")
 				w.Write(n.URL(source))
 			} else {
-				io.WriteString(w, "[-::]")
+				io.WriteString(w, "[-::]"+after)
 			}
 		case *ast.Link:
+			before, after := r.linkTags(w, string(n.Destination))
 			if entering {
 				linkColor := r.config.Options["linkColor"].(string)
-				io.WriteString(w, fmt.Sprintf("[%s:::%s]", linkColor, n.Destination))
+				io.WriteString(w, before+fmt.Sprintf("[%s:::%s]", linkColor, n.Destination))
 			} else {
-				io.WriteString(w, "[-:::-]")
+				io.WriteString(w, "[-:::-]"+after)
 			}
 		case *ast.Text:
 			if entering {
@@ -90,18 +105,32 @@ func (r *renderer) Render(w io.Writer, source []byte, n ast.Node) error {
 			}
 		case *discordmd.Mention:
 			if entering {
-				io.WriteString(w, "[::b]")
+				var gID discord.GuildID
+				if n.Message != nil {
+					gID = n.Message.GuildID
+				}
 
+				name, color := "", "-"
 				switch {
 				case n.Channel != nil:
-					io.WriteString(w, "#"+n.Channel.Name)
+					name = "#" + n.Channel.Name
 				case n.GuildUser != nil:
-					io.WriteString(w, "@"+n.GuildUser.Username)
+					name = "@" + n.GuildUser.Username
+					if fn, ok := r.config.Options["userMention"].(MentionFunc[discord.GuildUser]); ok {
+						name, color = fn(gID, *n.GuildUser)
+						name = "@" + name
+					}
 				case n.GuildRole != nil:
-					io.WriteString(w, "@"+n.GuildRole.Name)
+					name = "@" + n.GuildRole.Name
+					if fn, ok := r.config.Options["roleMention"].(MentionFunc[discord.Role]); ok {
+						name, color = fn(gID, *n.GuildRole)
+						name = "@" + name
+					}
 				}
+
+				io.WriteString(w, "["+color+"::b]"+tview.Escape(name))
 			} else {
-				io.WriteString(w, "[::-]")
+				io.WriteString(w, "[-::-]")
 			}
 		case *discordmd.Emoji:
 			if entering {
@@ -116,3 +145,11 @@ func (r *renderer) Render(w io.Writer, source []byte, n ast.Node) error {
 		return ast.WalkContinue, nil
 	})
 }
+
+func (r *renderer) linkTags(w io.Writer, url string) (string, string) {
+	if fn, ok := r.config.Options["linkTags"].(LinkTagsFunc); ok {
+		return fn(w, url)
+	}
+
+	return "", ""
+}
@@ -0,0 +1,71 @@
+// Package notify shows desktop notifications, either over D-Bus or with the escape sequences of the terminal.
+package notify
+
+import (
+	"os"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+type Method string
+
+const (
+	// D-Bus, falling back to the OSC 9 escape sequence along with the bell.
+	Auto   Method = "auto"
+	DBus   Method = "dbus"
+	OSC9   Method = "osc9"
+	OSC777 Method = "osc777"
+	Bell   Method = "bell"
+)
+
+// Sends the notification to the notification server of the session over the freedesktop notifications interface.
+func SendDBus(appName, title, body string) error {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return err
+	}
+
+	obj := conn.Object("org.freedesktop.Notifications", "/org/freedesktop/Notifications")
+	call := obj.Call("org.freedesktop.Notifications.Notify", 0,
+		appName, uint32(0), "", title, body, []string{}, map[string]dbus.Variant{}, int32(-1))
+	return call.Err
+}
+
+// Returns the escape sequence that shows the notification in the terminal, wrapped so that it passes through tmux if the terminal is running in it.
+func Escape(method Method, title, body string) string {
+	title, body = sanitize(title), sanitize(body)
+
+	var s string
+	switch method {
+	case OSC9, Auto:
+		s = "\x1b]9;" + title + ": " + body + "\x07"
+	case OSC777:
+		s = "\x1b]777;notify;" + strings.ReplaceAll(title, ";", ",") + ";" + body + "\x07"
+	}
+
+	if s != "" && os.Getenv("TMUX") != "" {
+		s = "\x1bPtmux;" + strings.ReplaceAll(s, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+
+	// The bell also marks the window of tmux as having activity.
+	if method == Bell || method == Auto {
+		s += "\a"
+	}
+
+	return s
+}
+
+// Removes the control characters, which would end the escape sequence early.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\t':
+			return ' '
+		case r < 0x20 || r == 0x7f || (r >= 0x80 && r < 0xa0):
+			return -1
+		}
+
+		return r
+	}, s)
+}